@@ -2,19 +2,44 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"os"
 
 	"github.com/soulmate-dating/profiles/internal/app"
 	"github.com/soulmate-dating/profiles/internal/config"
+	"github.com/soulmate-dating/profiles/internal/logger"
 	"github.com/soulmate-dating/profiles/internal/ports/grpc"
 )
 
 func main() {
-	ctx := context.Background()
+	// The context ends the background work of the app once the servers
+	// are shut down.
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
 	}
+	l, err := logger.New(os.Stdout, logger.Config{
+		Level:        cfg.Log.Level,
+		RedactFields: cfg.Log.RedactFields,
+	})
+	if err != nil {
+		slog.Error("failed to create logger", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(l)
+
 	appSvc := app.New(ctx, cfg)
 	grpc.Run(ctx, cfg, appSvc)
+	stop()
+
+	// Content moderated in the background is given the moderation timeout
+	// to be stored.
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.Moderation.Timeout)
+	defer cancel()
+	if err = appSvc.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to shutdown app", "error", err)
+	}
 }
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// LRU keeps values in process memory, evicting the least recently used
+// ones beyond its size and the ones older than the TTL.
+type LRU struct {
+	lru *expirable.LRU[string, []byte]
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{lru: expirable.NewLRU[string, []byte](size, nil, ttl)}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := c.lru.Get(key)
+	return value, ok, nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte) error {
+	c.lru.Add(key, value)
+	return nil
+}
+
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.lru.Remove(key)
+	}
+	return nil
+}
+
+func (c *LRU) Purge(context.Context) error {
+	c.lru.Purge()
+	return nil
+}
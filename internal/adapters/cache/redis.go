@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const purgeBatchSize = 500
+
+// Redis keeps values in a Redis compatible server shared by all instances
+// of the service. Keys are namespaced by the prefix.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+func NewRedis(client redis.UniversalClient, prefix string, ttl time.Duration) *Redis {
+	return &Redis{client: client, prefix: prefix, ttl: ttl}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte) error {
+	if err := c.client.Set(ctx, c.prefix+key, value, c.ttl).Err(); err != nil {
+		return fmt.Errorf("set %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	if err := unlink(ctx, c.client, prefixed); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes every key of the prefix.
+func (c *Redis) Purge(ctx context.Context) error {
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return c.purge(ctx, node)
+		})
+	}
+	return c.purge(ctx, c.client)
+}
+
+func (c *Redis) purge(ctx context.Context, client redis.UniversalClient) error {
+	iter := client.Scan(ctx, 0, c.prefix+"*", purgeBatchSize).Iterator()
+	batch := make([]string, 0, purgeBatchSize)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) < purgeBatchSize {
+			continue
+		}
+		if err := unlink(ctx, client, batch); err != nil {
+			return fmt.Errorf("purge: %w", err)
+		}
+		batch = batch[:0]
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+	if err := unlink(ctx, client, batch); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+	return nil
+}
+
+// unlink deletes the keys one by one, as the keys of a batch may belong
+// to different cluster slots.
+func unlink(ctx context.Context, client redis.UniversalClient, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, key := range keys {
+			p.Unlink(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
@@ -3,41 +3,166 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Config struct {
-	Host              string
-	Port              int
-	User              string
-	Password          string
-	DBName            string
-	SSLMode           string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+	// ConnectionTimeout bounds the time Connect waits for the primary
+	// to accept connections.
 	ConnectionTimeout time.Duration
+	// DialTimeout bounds a single connection attempt.
+	DialTimeout time.Duration
+	// RetryInitialBackoff and RetryMaxBackoff space the connection attempts.
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+
+	MaxConns              int32
+	MinConns              int32
+	MaxConnLifetime       time.Duration
+	MaxConnLifetimeJitter time.Duration
+	MaxConnIdleTime       time.Duration
+	HealthCheckPeriod     time.Duration
+	StatementTimeout      time.Duration
+	ApplicationName       string
+
+	// Replicas are the host:port addresses of read replicas sharing the
+	// credentials of the primary.
+	Replicas []string
 }
 
-func Connect(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
-	connString := getConnectionString(cfg)
-	poolCfg, err := pgxpool.ParseConfig(connString)
+// Cluster holds the connection pools of the primary and its replicas.
+type Cluster struct {
+	Primary  *pgxpool.Pool
+	Replicas []*pgxpool.Pool
+}
+
+// Connect connects to the primary, retrying until it accepts connections or
+// the connection timeout expires. Replicas are connected lazily, so that the
+// service starts while they are down.
+func Connect(ctx context.Context, cfg Config) (*Cluster, error) {
+	primary, err := newPool(ctx, cfg, cfg.Host, cfg.Port)
 	if err != nil {
 		return nil, err
 	}
-	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	err = waitForDatabase(ctx, primary, cfg)
 	if err != nil {
+		primary.Close()
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
-	err = pool.Ping(ctx)
+
+	cluster := &Cluster{Primary: primary}
+	for _, address := range cfg.Replicas {
+		host, port, err := splitAddress(address, cfg.Port)
+		if err != nil {
+			cluster.Close()
+			return nil, fmt.Errorf("replica %q: %w", address, err)
+		}
+		replica, err := newPool(ctx, cfg, host, port)
+		if err != nil {
+			cluster.Close()
+			return nil, fmt.Errorf("replica %q: %w", address, err)
+		}
+		cluster.Replicas = append(cluster.Replicas, replica)
+	}
+	return cluster, nil
+}
+
+// ReplicaConnections returns the replicas as connections of a Pool.
+func (c *Cluster) ReplicaConnections() []ReplicaConnection {
+	conns := make([]ReplicaConnection, len(c.Replicas))
+	for i, r := range c.Replicas {
+		conns[i] = r
+	}
+	return conns
+}
+
+func (c *Cluster) Close() {
+	c.Primary.Close()
+	for _, r := range c.Replicas {
+		r.Close()
+	}
+}
+
+func newPool(ctx context.Context, cfg Config, host string, port int) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(getConnectionString(cfg, host, port))
 	if err != nil {
 		return nil, err
 	}
-	return pool, err
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	poolCfg.MinConns = cfg.MinConns
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	poolCfg.MaxConnLifetimeJitter = cfg.MaxConnLifetimeJitter
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	if cfg.DialTimeout > 0 {
+		poolCfg.ConnConfig.ConnectTimeout = cfg.DialTimeout
+	}
+	if cfg.StatementTimeout > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.ApplicationName != "" {
+		poolCfg.ConnConfig.RuntimeParams["application_name"] = cfg.ApplicationName
+	}
+	return pgxpool.NewWithConfig(ctx, poolCfg)
+}
+
+// waitForDatabase pings the database with exponential backoff until it
+// answers, so that the service survives the database starting after it.
+func waitForDatabase(ctx context.Context, pool *pgxpool.Pool, cfg Config) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectionTimeout)
+	defer cancel()
+
+	backoff := cfg.RetryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("database is not reachable after %s: %w", cfg.ConnectionTimeout, err)
+		}
+		slog.WarnContext(ctx, "database is not reachable yet", "attempt", attempt, "retry_in", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database is not reachable after %s: %w", cfg.ConnectionTimeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, cfg.RetryMaxBackoff)
+	}
+}
+
+func splitAddress(address string, defaultPort int) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return address, defaultPort, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port: %w", err)
+	}
+	return host, port, nil
 }
 
-func getConnectionString(cfg Config) string {
+func getConnectionString(cfg Config, host string, port int) string {
 	return fmt.Sprintf("host=%s port=%d dbname=%s user=%s password=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.DBName, cfg.User, cfg.Password, cfg.SSLMode)
+		host, port, cfg.DBName, cfg.User, cfg.Password, cfg.SSLMode)
 }
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+const (
+	uniqueViolationCode           = "23505"
+	foreignKeyViolationCode       = "23503"
+	checkViolationCode            = "23514"
+	invalidTextRepresentationCode = "22P02"
+)
+
+// translateError turns constraint violations and rejected values into
+// domain errors, so that they are not reported as internal errors.
+// Other errors are returned unchanged. Statements returning rows report
+// their errors when the rows are read, so reading the rows of writes is
+// translated too.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case uniqueViolationCode:
+		return fmt.Errorf("%w: violates %s", domain.ErrNotUnique, pgErr.ConstraintName)
+	case foreignKeyViolationCode:
+		return fmt.Errorf("%w: referenced entity does not exist (%s)", domain.ErrNotFound, pgErr.ConstraintName)
+	case checkViolationCode:
+		return &domain.ValidationError{
+			Constraint: pgErr.ConstraintName,
+			Column:     pgErr.ColumnName,
+			Reason:     "violates check constraint",
+		}
+	case invalidTextRepresentationCode:
+		return &domain.ValidationError{
+			Constraint: pgErr.ConstraintName,
+			Column:     pgErr.ColumnName,
+			Reason:     pgErr.Message,
+		}
+	}
+	return err
+}
+
+// translateDeleteError is translateError for deletes, which violate foreign
+// keys when other rows still reference the deleted ones.
+func translateDeleteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return fmt.Errorf("%w: %s", domain.ErrStillReferenced, pgErr.ConstraintName)
+	}
+	return translateError(err)
+}
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/soulmate-dating/profiles/internal/domain"
 )
@@ -22,3 +24,32 @@ func NewPromptBatch(prompts []domain.Prompt) PromptBatch {
 		Positions: positions,
 	}
 }
+
+func NewGalleryImageBatch(images []domain.GalleryImage) PromptBatch {
+	ids := make([]uuid.UUID, len(images))
+	positions := make([]int32, len(images))
+	for i, image := range images {
+		ids[i] = image.ID
+		positions[i] = image.Position
+	}
+	return PromptBatch{
+		IDs:       ids,
+		Positions: positions,
+	}
+}
+
+// optionalTime passes zero times as NULL.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func contentTypesToStrings(types []domain.ContentType) []string {
+	res := make([]string, len(types))
+	for i, t := range types {
+		res[i] = string(t)
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterPoolMetrics exports the statistics of the connection pool under the given name.
+func RegisterPoolMetrics(name string, pool *pgxpool.Pool) error {
+	labels := prometheus.Labels{"pool": name}
+	gauge := func(metric, help string, value func(s *pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   "profiles",
+			Subsystem:   "db_pool",
+			Name:        metric,
+			Help:        help,
+			ConstLabels: labels,
+		}, func() float64 { return value(pool.Stat()) })
+	}
+	counter := func(metric, help string, value func(s *pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   "profiles",
+			Subsystem:   "db_pool",
+			Name:        metric,
+			Help:        help,
+			ConstLabels: labels,
+		}, func() float64 { return value(pool.Stat()) })
+	}
+
+	collectors := []prometheus.Collector{
+		gauge("acquired_conns", "Number of currently acquired connections.", func(s *pgxpool.Stat) float64 {
+			return float64(s.AcquiredConns())
+		}),
+		gauge("idle_conns", "Number of currently idle connections.", func(s *pgxpool.Stat) float64 {
+			return float64(s.IdleConns())
+		}),
+		gauge("total_conns", "Total number of connections in the pool.", func(s *pgxpool.Stat) float64 {
+			return float64(s.TotalConns())
+		}),
+		gauge("max_conns", "Maximum size of the pool.", func(s *pgxpool.Stat) float64 {
+			return float64(s.MaxConns())
+		}),
+		counter("acquires_total", "Number of successful connection acquires.", func(s *pgxpool.Stat) float64 {
+			return float64(s.AcquireCount())
+		}),
+		counter("empty_acquires_total", "Number of acquires that had to wait for a connection.", func(s *pgxpool.Stat) float64 {
+			return float64(s.EmptyAcquireCount())
+		}),
+		counter("acquire_wait_seconds_total", "Total time spent acquiring connections.", func(s *pgxpool.Stat) float64 {
+			return s.AcquireDuration().Seconds()
+		}),
+	}
+	for _, c := range collectors {
+		if err := prometheus.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
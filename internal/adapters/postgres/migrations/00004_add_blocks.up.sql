@@ -0,0 +1,10 @@
+CREATE TABLE profiles.blocks
+(
+    blocker_id uuid REFERENCES profiles.profiles (user_id),
+    blocked_id uuid REFERENCES profiles.profiles (user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT blocks_not_self CHECK (blocker_id != blocked_id)
+);
+
+CREATE INDEX blocks_blocked_id_idx ON profiles.blocks (blocked_id);
@@ -0,0 +1,12 @@
+CREATE TYPE VISIBILITY AS ENUM ('visible', 'paused', 'incognito');
+
+ALTER TABLE profiles.profiles
+    ADD COLUMN visibility VISIBILITY NOT NULL DEFAULT 'visible';
+
+CREATE TABLE profiles.allowed_viewers
+(
+    user_id    uuid REFERENCES profiles.profiles (user_id),
+    viewer_id  uuid REFERENCES profiles.profiles (user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, viewer_id)
+);
@@ -0,0 +1,33 @@
+CREATE TYPE REPORT_REASON AS ENUM (
+    'fake profile',
+    'inappropriate content',
+    'harassment',
+    'spam',
+    'underage',
+    'other'
+    );
+CREATE TYPE REPORT_STATUS AS ENUM ('open', 'claimed', 'resolved');
+CREATE TYPE MODERATION_ACTION AS ENUM ('dismiss', 'remove prompt', 'suspend profile');
+
+ALTER TABLE profiles.profiles
+    ADD COLUMN suspended BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE profiles.reports
+(
+    id              uuid,
+    reporter_id     uuid          NOT NULL REFERENCES profiles.profiles (user_id),
+    reported_id     uuid          NOT NULL REFERENCES profiles.profiles (user_id),
+    reason          REPORT_REASON NOT NULL,
+    prompt_id       uuid,
+    comment         TEXT          NOT NULL DEFAULT '',
+    status          REPORT_STATUS NOT NULL DEFAULT 'open',
+    moderator_id    uuid,
+    action          MODERATION_ACTION,
+    resolution_note TEXT          NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    claimed_at      TIMESTAMPTZ,
+    resolved_at     TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX reports_status_created_at_idx ON profiles.reports (status, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE profiles.prompt_questions
+(
+    id            uuid,
+    texts         JSONB         NOT NULL,
+    category      TEXT          NOT NULL DEFAULT '',
+    content_types PROMPT_TYPE[] NOT NULL,
+    active        BOOLEAN       NOT NULL DEFAULT true,
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    PRIMARY KEY (id)
+);
+
+ALTER TABLE profiles.prompts
+    ADD COLUMN question_id uuid REFERENCES profiles.prompt_questions (id);
+
+CREATE UNIQUE INDEX unique_prompt_question ON profiles.prompts (user_id, question_id, type)
+    WHERE question_id IS NOT NULL;
@@ -0,0 +1,8 @@
+UPDATE profiles.prompts p
+SET position = ordered.new_position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY position, id) - 1 AS new_position
+      FROM profiles.prompts) AS ordered
+WHERE p.id = ordered.id;
+
+ALTER TABLE profiles.prompts
+    ADD CONSTRAINT unique_prompt_position UNIQUE (user_id, position) DEFERRABLE INITIALLY DEFERRED;
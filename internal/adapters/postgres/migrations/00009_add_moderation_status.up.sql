@@ -0,0 +1,4 @@
+CREATE TYPE MODERATION_STATUS AS ENUM ('approved', 'pending', 'rejected');
+
+ALTER TABLE profiles.prompts
+    ADD COLUMN moderation_status MODERATION_STATUS NOT NULL DEFAULT 'approved';
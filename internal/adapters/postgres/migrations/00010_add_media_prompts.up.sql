@@ -0,0 +1,6 @@
+ALTER TYPE PROMPT_TYPE ADD VALUE 'audio';
+ALTER TYPE PROMPT_TYPE ADD VALUE 'video';
+
+ALTER TABLE profiles.prompts
+    ADD COLUMN duration_ms    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN thumbnail_link TEXT    NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+ALTER TYPE PROMPT_TYPE ADD VALUE 'gallery';
+
+CREATE TABLE profiles.gallery_images
+(
+    id                uuid PRIMARY KEY,
+    prompt_id         uuid              NOT NULL REFERENCES profiles.prompts (id) ON DELETE CASCADE,
+    link              TEXT              NOT NULL,
+    caption           TEXT              NOT NULL DEFAULT '',
+    position          INTEGER           NOT NULL,
+    moderation_status MODERATION_STATUS NOT NULL DEFAULT 'approved',
+    created_at        TIMESTAMPTZ       NOT NULL DEFAULT now(),
+    CONSTRAINT unique_gallery_image_position UNIQUE (prompt_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,30 @@
+ALTER TYPE REPORT_REASON ADD VALUE 'duplicate photo';
+
+-- Reports raised by the service itself have no reporter.
+ALTER TABLE profiles.reports
+    ALTER COLUMN reporter_id DROP NOT NULL;
+
+CREATE TABLE profiles.media_uploads
+(
+    user_id         uuid        NOT NULL,
+    sha256          BYTEA       NOT NULL,
+    perceptual_hash BIGINT,
+    link            TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, sha256)
+);
+
+CREATE INDEX media_uploads_sha256_idx ON profiles.media_uploads (sha256);
+
+-- Perceptual hashes within 4 bits of each other share at least one of these
+-- 5 bands, so similar photos are found without comparing every upload.
+CREATE INDEX media_uploads_perceptual_hash_band0_idx ON profiles.media_uploads ((perceptual_hash & 8191))
+    WHERE perceptual_hash IS NOT NULL;
+CREATE INDEX media_uploads_perceptual_hash_band1_idx ON profiles.media_uploads (((perceptual_hash >> 13) & 8191))
+    WHERE perceptual_hash IS NOT NULL;
+CREATE INDEX media_uploads_perceptual_hash_band2_idx ON profiles.media_uploads (((perceptual_hash >> 26) & 8191))
+    WHERE perceptual_hash IS NOT NULL;
+CREATE INDEX media_uploads_perceptual_hash_band3_idx ON profiles.media_uploads (((perceptual_hash >> 39) & 8191))
+    WHERE perceptual_hash IS NOT NULL;
+CREATE INDEX media_uploads_perceptual_hash_band4_idx ON profiles.media_uploads (((perceptual_hash >> 52) & 4095))
+    WHERE perceptual_hash IS NOT NULL;
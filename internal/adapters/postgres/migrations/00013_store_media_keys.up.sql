@@ -0,0 +1,15 @@
+-- Media files are stored as object keys instead of public links;
+-- links are signed when the files are returned to clients.
+UPDATE profiles.prompts
+SET content = regexp_replace(content, '^[a-z][a-z0-9+.-]*://[^/]+/([^?#]*).*$', '\1')
+WHERE type <> 'text';
+
+UPDATE profiles.prompts
+SET thumbnail_link = regexp_replace(thumbnail_link, '^[a-z][a-z0-9+.-]*://[^/]+/([^?#]*).*$', '\1')
+WHERE thumbnail_link <> '';
+
+UPDATE profiles.gallery_images
+SET link = regexp_replace(link, '^[a-z][a-z0-9+.-]*://[^/]+/([^?#]*).*$', '\1');
+
+UPDATE profiles.media_uploads
+SET link = regexp_replace(link, '^[a-z][a-z0-9+.-]*://[^/]+/([^?#]*).*$', '\1');
@@ -0,0 +1,9 @@
+-- Renditions map rendition names to the object keys of scaled down images.
+ALTER TABLE profiles.prompts
+    ADD COLUMN renditions JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE profiles.gallery_images
+    ADD COLUMN renditions JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE profiles.media_uploads
+    ADD COLUMN renditions JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+ALTER TABLE profiles.profiles
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Keyset pagination of the profile listing.
+CREATE INDEX profiles_created_at_user_id_idx ON profiles.profiles (created_at DESC, user_id DESC);
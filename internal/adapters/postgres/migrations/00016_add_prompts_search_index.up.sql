@@ -0,0 +1,17 @@
+-- The expression must match the text searched by searchPromptsQuery in queries.go
+-- for the index to be used. Only text prompts contribute their content; the
+-- content of media prompts is a storage key.
+--
+-- The index is built for the default PROMPTS_SEARCH_LANGUAGE. Deployments searching
+-- in another language create the matching index before switching, e.g. for german:
+--   CREATE INDEX CONCURRENTLY prompts_search_german_idx ON profiles.prompts USING GIN (
+--       to_tsvector('german', question || ' ' || CASE WHEN type = 'text' THEN content ELSE '' END)
+--   );
+-- and check that pg_index.indisvalid is true for it, as a failed build leaves an
+-- invalid index that is not used.
+--
+-- CONCURRENTLY keeps prompts writable while the index is built. It cannot run in
+-- a transaction, so this migration holds this single statement.
+CREATE INDEX CONCURRENTLY prompts_search_english_idx ON profiles.prompts USING GIN (
+    to_tsvector('english', question || ' ' || CASE WHEN type = 'text' THEN content ELSE '' END)
+);
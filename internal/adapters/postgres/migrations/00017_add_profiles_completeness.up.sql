@@ -0,0 +1,27 @@
+-- Completeness is computed by the service on every change of the profile or
+-- its prompts. Existing profiles are scored here as domain.NewCompleteness
+-- does.
+ALTER TABLE profiles.profiles
+    ADD COLUMN completeness INTEGER NOT NULL DEFAULT 0;
+
+UPDATE profiles.profiles p
+SET completeness =
+        30 * LEAST(
+                (SELECT count(*)
+                 FROM profiles.prompts pr
+                 WHERE pr.user_id = p.user_id AND pr.type = 'image' AND pr.moderation_status != 'rejected') +
+                (SELECT count(*)
+                 FROM profiles.gallery_images gi
+                          JOIN profiles.prompts pr ON pr.id = gi.prompt_id
+                 WHERE pr.user_id = p.user_id AND pr.type = 'gallery' AND pr.moderation_status != 'rejected'
+                   AND gi.moderation_status != 'rejected'), 3) / 3 +
+        25 * LEAST((SELECT count(*)
+                    FROM profiles.prompts pr
+                    WHERE pr.user_id = p.user_id AND pr.type = 'text' AND pr.moderation_status != 'rejected'), 3) / 3 +
+        15 * (p.fk_main_pic_prompt IS NOT NULL)::int +
+        10 * LEAST((SELECT count(*)
+                    FROM profiles.prompts pr
+                    WHERE pr.user_id = p.user_id AND pr.type IN ('audio', 'video') AND pr.moderation_status != 'rejected'), 1) +
+        10 * (COALESCE(p.location, '') != '')::int +
+        5 * (COALESCE(p.last_name, '') != '')::int +
+        5 * (COALESCE(p.height, 0) > 0)::int;
@@ -2,14 +2,37 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+)
+
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
 )
 
 type TxCtxKey struct{}
 
+type primaryCtxKey struct{}
+
+// WithPrimary returns a context whose reads outside transactions and
+// read-only transactions run on the primary, for reads that must see the
+// latest writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func onPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return primary
+}
+
 type Connection interface {
 	Begin(ctx context.Context) (pgx.Tx, error)
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
@@ -17,24 +40,56 @@ type Connection interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 }
 
+// Database is a connection pool that can begin transactions with options.
+type Database interface {
+	Connection
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
 type ConnPool interface {
 	GetTx(ctx context.Context) Connection
-	RunInTx(ctx context.Context, f func(context.Context) error) error
+	RunInTx(ctx context.Context, f func(context.Context) error, opts ...TxOption) error
 }
 
+// RetryPolicy bounds the retries of transactions failing with a
+// serialization failure or a deadlock.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Pool runs queries on the primary and, when replicas are configured,
+// sends reads outside transactions and read-only transactions to them.
 type Pool struct {
-	pool Connection
+	pool     Database
+	replicas *replicaSet
+	retry    RetryPolicy
+}
+
+func NewPool(pool Database, replicas ...ReplicaConnection) *Pool {
+	return &Pool{
+		pool:     pool,
+		replicas: newReplicaSet(replicas),
+		retry:    RetryPolicy{MaxAttempts: 1},
+	}
 }
 
-func NewPool(pool Connection) *Pool {
-	return &Pool{pool: pool}
+func (p *Pool) SetRetryPolicy(retry RetryPolicy) {
+	p.retry = retry
 }
 
+// GetTx returns the transaction of the context. Outside transactions it
+// returns a connection sending SELECT statements to a replica, unless the
+// context was created with WithPrimary.
 func (p *Pool) GetTx(ctx context.Context) Connection {
 	if tx := p.AcquireTx(ctx); tx != nil {
 		return tx
 	}
-	return p.pool
+	if p.replicas.empty() || onPrimary(ctx) {
+		return p.pool
+	}
+	return &router{primary: p.pool, replicas: p.replicas}
 }
 
 func (p *Pool) AcquireTx(ctx context.Context) pgx.Tx {
@@ -43,25 +98,79 @@ func (p *Pool) AcquireTx(ctx context.Context) pgx.Tx {
 	return tx
 }
 
-func (p *Pool) RunInTx(ctx context.Context, f func(context.Context) error) error {
+// RunInTx runs f in a transaction, retrying it with backoff when it fails
+// with a serialization failure or a deadlock. Inside another transaction f
+// joins it and the outermost transaction is retried instead. As f may run
+// several times, it must not have effects outside the database.
+func (p *Pool) RunInTx(ctx context.Context, f func(context.Context) error, opts ...TxOption) error {
 	if tx := p.AcquireTx(ctx); tx != nil {
 		return f(ctx)
 	}
 
-	tx, err := p.pool.Begin(ctx)
+	options := NewTxOptions(opts...)
+	backoff := p.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.runInTx(ctx, f, options)
+		reason := retryReason(err)
+		if reason == "" || attempt >= p.retry.MaxAttempts {
+			return err
+		}
+		metrics.TxRetries.WithLabelValues(reason).Inc()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(withJitter(backoff)):
+		}
+		backoff = min(2*backoff, p.retry.MaxBackoff)
+	}
+}
+
+func (p *Pool) runInTx(ctx context.Context, f func(context.Context) error, options TxOptions) error {
+	txOptions := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(options.Isolation)}
+	if options.ReadOnly {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+	if options.Deferrable {
+		txOptions.DeferrableMode = pgx.Deferrable
+	}
+
+	// Hot standbys do not support serializable transactions.
+	if options.ReadOnly && !options.OnPrimary && !onPrimary(ctx) && options.Isolation != Serializable {
+		for r := p.replicas.next(); r != nil; r = p.replicas.next() {
+			tx, err := r.conn.BeginTx(ctx, txOptions)
+			if err == nil {
+				metrics.DBReads.WithLabelValues("replica").Inc()
+				return p.runTx(ctx, tx, f)
+			}
+			if ctx.Err() != nil {
+				return fmt.Errorf("begin transaction: %w", err)
+			}
+			r.markDown(ctx, err)
+		}
+	}
+
+	tx, err := p.pool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
+	if options.ReadOnly {
+		metrics.DBReads.WithLabelValues("primary").Inc()
+	}
+	return p.runTx(ctx, tx, f)
+}
 
+func (p *Pool) runTx(ctx context.Context, tx pgx.Tx, f func(context.Context) error) error {
 	defer func() {
 		if r := recover(); r != nil {
 			_ = tx.Rollback(ctx)
+			metrics.TxRollbacks.WithLabelValues("panic").Inc()
 			panic(r)
 		}
 	}()
 
 	if err := f(context.WithValue(ctx, TxCtxKey{}, tx)); err != nil {
 		errRollback := tx.Rollback(ctx)
+		metrics.TxRollbacks.WithLabelValues("error").Inc()
 		if errRollback != nil {
 			err = fmt.Errorf("%w - rollback transaction: %w", err, errRollback)
 		}
@@ -69,8 +178,33 @@ func (p *Pool) RunInTx(ctx context.Context, f func(context.Context) error) error
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		metrics.TxRollbacks.WithLabelValues("commit").Inc()
+		return fmt.Errorf("commit transaction: %w", translateError(err))
 	}
 
 	return nil
 }
+
+// retryReason returns the metric label of errors worth retrying the
+// transaction for, or an empty string.
+func retryReason(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	switch pgErr.Code {
+	case serializationFailureCode:
+		return "serialization_failure"
+	case deadlockDetectedCode:
+		return "deadlock"
+	}
+	return ""
+}
+
+// withJitter spreads the retries of transactions that conflicted with each other.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
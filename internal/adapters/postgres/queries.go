@@ -6,24 +6,188 @@ const (
                       		user_id, first_name, last_name, birth_date, sex, preferred_partner, intention, 
     						height, has_children, family_plans, location,
     						drinks_alcohol, smokes
-    						) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+    						) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING created_at`
+	// userPhotos lists the moderation status of the photos of the profile p:
+	// its image prompts and the images of its gallery prompts. A gallery
+	// image has the worse status of the image and its prompt; the statuses
+	// are ordered from approved to rejected.
+	userPhotos = `(
+							SELECT pr.moderation_status FROM profiles.prompts pr
+								WHERE pr.user_id = p.user_id AND pr.type = 'image'
+							UNION ALL
+							SELECT GREATEST(pr.moderation_status, gi.moderation_status)
+								FROM profiles.gallery_images gi JOIN profiles.prompts pr ON pr.id = gi.prompt_id
+								WHERE pr.user_id = p.user_id AND pr.type = 'gallery'
+						) AS photo(status)`
+	// The score is computed as domain.NewCompleteness does.
+	updateProfileCompletenessQuery = `UPDATE profiles.profiles p SET completeness =
+							30 * LEAST((SELECT count(*) FROM ` + userPhotos + ` WHERE photo.status != 'rejected'), 3) / 3 +
+							25 * LEAST((SELECT count(*) FROM profiles.prompts pr
+								WHERE pr.user_id = p.user_id AND pr.type = 'text' AND pr.moderation_status != 'rejected'), 3) / 3 +
+							15 * (p.fk_main_pic_prompt IS NOT NULL)::int +
+							10 * LEAST((SELECT count(*) FROM profiles.prompts pr
+								WHERE pr.user_id = p.user_id AND pr.type IN ('audio', 'video') AND pr.moderation_status != 'rejected'), 1) +
+							10 * (COALESCE(p.location, '') != '')::int +
+							5 * (COALESCE(p.last_name, '') != '')::int +
+							5 * (COALESCE(p.height, 0) > 0)::int
+							WHERE p.user_id = $1`
 	updateProfileQuery = `UPDATE profiles.profiles 
 							SET first_name = $2, last_name = $3, birth_date = $4,
 							    sex = $5, preferred_partner = $6, intention = $7, height = $8,
 							    has_children = $9, family_plans = $10, location = $11,
 							    drinks_alcohol = $12, smokes = $13, fk_main_pic_prompt = $14 WHERE user_id = $1 RETURNING *`
-	getRandomProfileBySexAndPreferenceQuery = `SELECT * FROM profiles.profiles WHERE (user_id != $1 AND (sex = $2 OR sex = $3) AND (preferred_partner = $4 OR preferred_partner = 'anyone')) ORDER BY RANDOM() LIMIT 1`
-	getMultipleProfilesByIDsQuery           = `SELECT * FROM profiles.profiles WHERE user_id = ANY($1)`
-	createPromptQuery                       = `INSERT INTO profiles.prompts (id, user_id, question, content, type, position) VALUES ($1, $2, $3, $4, $5, $6)`
-	getPromptsByUserQuery                   = `SELECT * FROM profiles.prompts WHERE user_id = $1 ORDER BY position ASC`
-	getPromptByIDQuery                      = `SELECT * FROM profiles.prompts WHERE id = $1`
-	getPromptByUserQuestionAndTypeQuery     = `SELECT * FROM profiles.prompts WHERE user_id = $1 AND question = $2 AND type = $3`
-	updatePromptQuery                       = `UPDATE profiles.prompts SET question = $2, content = $3, position = $4 WHERE id = $1 RETURNING *`
-	getPromptsByIDsQuery                    = `SELECT * FROM profiles.prompts WHERE id = ANY($1)`
-	updatePromptsPositionQuery              = `
+	getRandomProfileBySexAndPreferenceQuery = `SELECT p.* FROM profiles.profiles p WHERE (p.user_id != $1 AND (p.sex = $2 OR p.sex = $3) AND (p.preferred_partner = $4 OR p.preferred_partner = 'anyone')) AND (SELECT count(*) FROM ` + userPhotos + ` WHERE photo.status = 'approved') >= $5 AND p.completeness >= $6 AND ` + viewerCondition + ` ORDER BY RANDOM() LIMIT 1`
+	getMultipleProfilesByIDsQuery           = `SELECT p.* FROM profiles.profiles p WHERE p.user_id = ANY($2) AND (p.user_id = $1 OR ` + viewerCondition + `)`
+	getProfileForViewerQuery                = `SELECT p.* FROM profiles.profiles p WHERE p.user_id = $2 AND ` + viewerCondition
+
+	// Profiles are listed from the newest; the cursor ($9, $10) is the last profile of the previous page.
+	listProfilesQuery = `SELECT p.* FROM profiles.profiles p WHERE
+							($1 = '' OR p.sex::text = $1) AND
+							($2 = 0 OR p.birth_date <= current_date - make_interval(years => $2)) AND
+							($3 = 0 OR p.birth_date > current_date - make_interval(years => $3 + 1)) AND
+							($4 = '' OR p.location ILIKE '%' || $4 || '%' ESCAPE '\') AND
+							($5 = '' OR p.intention::text = $5) AND
+							($6::timestamptz IS NULL OR p.created_at >= $6) AND
+							($7::timestamptz IS NULL OR p.created_at < $7) AND
+							($8::boolean IS NULL OR $8 = EXISTS (
+								SELECT 1 FROM profiles.prompts pr WHERE pr.user_id = p.user_id AND pr.type IN ('image', 'gallery')
+							)) AND
+							($9::timestamptz IS NULL OR (p.created_at, p.user_id) < ($9, $10::uuid))
+							ORDER BY p.created_at DESC, p.user_id DESC LIMIT $11`
+
+	createPromptQuery                   = `INSERT INTO profiles.prompts (id, user_id, question, content, type, position, question_id, moderation_status, duration_ms, thumbnail_link, renditions) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11::jsonb, '{}'))`
+	getPromptsByUserQuery               = `SELECT * FROM profiles.prompts WHERE user_id = $1 ORDER BY position ASC`
+	getPromptByIDQuery                  = `SELECT * FROM profiles.prompts WHERE id = $1`
+	getPromptByUserQuestionAndTypeQuery = `SELECT * FROM profiles.prompts WHERE user_id = $1 AND question = $2 AND type = $3`
+	updatePromptQuery                   = `UPDATE profiles.prompts SET question = $2, content = $3, position = $4, question_id = $5, moderation_status = $6, duration_ms = $7, thumbnail_link = $8, renditions = COALESCE($9::jsonb, '{}') WHERE id = $1 RETURNING *`
+	getPromptsByIDsQuery                = `SELECT * FROM profiles.prompts WHERE id = ANY($1)`
+	updatePromptsPositionQuery          = `
 		UPDATE profiles.prompts
 		SET position = updated.new_position
 		FROM (SELECT unnest($1::uuid[]) as new_id, unnest($2::int[]) as new_position) as updated
 		WHERE id = updated.new_id`
 	deletePromptQuery = `DELETE FROM profiles.prompts WHERE id = $1`
+
+	// The keys of the images match the fields of domain.GalleryImage.
+	getPromptsByUsersQuery = `
+		SELECT pr.*, COALESCE(gi.images, '[]') AS images
+		FROM profiles.prompts pr
+		LEFT JOIN LATERAL (
+			SELECT jsonb_agg(jsonb_build_object(
+				'id', g.id, 'promptId', g.prompt_id, 'link', g.link, 'caption', g.caption, 'position', g.position,
+				'moderationStatus', g.moderation_status, 'createdAt', g.created_at, 'renditions', g.renditions
+			) ORDER BY g.position) AS images
+			FROM profiles.gallery_images g
+			WHERE g.prompt_id = pr.id
+		) gi ON pr.type = 'gallery'
+		WHERE pr.user_id = ANY($1)
+		ORDER BY pr.user_id, pr.position ASC`
+
+	getGalleryImagesByPromptsQuery = `SELECT * FROM profiles.gallery_images WHERE prompt_id = ANY($1) ORDER BY prompt_id, position`
+	createGalleryImageQuery        = `INSERT INTO profiles.gallery_images (id, prompt_id, link, caption, position, moderation_status, renditions)
+							VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::jsonb, '{}')) RETURNING *`
+	deleteGalleryImageQuery          = `DELETE FROM profiles.gallery_images WHERE id = $1 AND prompt_id = $2 RETURNING *`
+	updateGalleryImagesPositionQuery = `
+		UPDATE profiles.gallery_images
+		SET position = updated.new_position
+		FROM (SELECT unnest($1::uuid[]) as new_id, unnest($2::int[]) as new_position) as updated
+		WHERE id = updated.new_id`
+	updateGalleryImageModerationStatusQuery = `UPDATE profiles.gallery_images SET moderation_status = $2 WHERE id = $1`
+
+	getMediaUploadQuery    = `SELECT * FROM profiles.media_uploads WHERE user_id = $1 AND sha256 = $2`
+	createMediaUploadQuery = `INSERT INTO profiles.media_uploads (user_id, sha256, perceptual_hash, link, renditions) VALUES ($1, $2, $3, $4, COALESCE($5::jsonb, '{}'))
+							ON CONFLICT (user_id, sha256) DO NOTHING`
+	findSameUploadsQuery = `SELECT * FROM profiles.media_uploads WHERE sha256 = $2 AND user_id != $1 ORDER BY created_at LIMIT $3`
+	// Hashes within MaxDuplicateDistance bits of each other share at least one
+	// of the bands, which are indexed by the media_uploads_perceptual_hash_band
+	// indexes. The hamming distance is the number of ones in the xor.
+	findSimilarUploadsQuery = `SELECT * FROM profiles.media_uploads
+							WHERE user_id != $1 AND perceptual_hash IS NOT NULL AND (
+								(perceptual_hash & 8191) = ($2::bigint & 8191) OR
+								((perceptual_hash >> 13) & 8191) = (($2::bigint >> 13) & 8191) OR
+								((perceptual_hash >> 26) & 8191) = (($2::bigint >> 26) & 8191) OR
+								((perceptual_hash >> 39) & 8191) = (($2::bigint >> 39) & 8191) OR
+								((perceptual_hash >> 52) & 4095) = (($2::bigint >> 52) & 4095))
+							AND length(replace((perceptual_hash # $2::bigint)::bit(64)::text, '0', '')) <= $3
+							ORDER BY created_at LIMIT $4`
+
+	updatePromptModerationStatusQuery = `UPDATE profiles.prompts SET moderation_status = $2 WHERE id = $1 AND question = $3 AND content = $4`
+
+	createBlockQuery = `INSERT INTO profiles.blocks (blocker_id, blocked_id) VALUES ($1, $2)
+							ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET blocker_id = EXCLUDED.blocker_id RETURNING *`
+	deleteBlockQuery        = `DELETE FROM profiles.blocks WHERE blocker_id = $1 AND blocked_id = $2 RETURNING *`
+	getBlocksByBlockerQuery = `SELECT * FROM profiles.blocks WHERE blocker_id = $1 ORDER BY created_at DESC`
+
+	updateProfileVisibilityQuery = `UPDATE profiles.profiles SET visibility = $2 WHERE user_id = $1 RETURNING *`
+	createAllowedViewerQuery     = `INSERT INTO profiles.allowed_viewers (user_id, viewer_id) VALUES ($1, $2)
+							ON CONFLICT (user_id, viewer_id) DO UPDATE SET user_id = EXCLUDED.user_id RETURNING *`
+	deleteAllowedViewerQuery     = `DELETE FROM profiles.allowed_viewers WHERE user_id = $1 AND viewer_id = $2 RETURNING *`
+	getAllowedViewersByUserQuery = `SELECT * FROM profiles.allowed_viewers WHERE user_id = $1 ORDER BY created_at DESC`
+
+	updateProfileSuspendedQuery = `UPDATE profiles.profiles SET suspended = $2 WHERE user_id = $1 RETURNING *`
+	createReportQuery           = `INSERT INTO profiles.reports (id, reporter_id, reported_id, reason, prompt_id, comment)
+							VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`
+	getReportByIDQuery = `SELECT * FROM profiles.reports WHERE id = $1`
+	hasOpenReportQuery = `SELECT EXISTS (SELECT 1 FROM profiles.reports WHERE reported_id = $1 AND reason = $2 AND status != 'resolved')`
+	getReportsQuery    = `SELECT * FROM profiles.reports WHERE ($1 = '' OR status::text = $1) ORDER BY created_at LIMIT $2 OFFSET $3`
+	claimReportQuery   = `UPDATE profiles.reports SET status = 'claimed', moderator_id = $2, claimed_at = now()
+							WHERE id = $1 AND status = 'open' RETURNING *`
+	resolveReportQuery = `UPDATE profiles.reports SET status = 'resolved', action = $3, resolution_note = $4, resolved_at = now()
+							WHERE id = $1 AND status = 'claimed' AND moderator_id = $2 RETURNING *`
+
+	promptQuestionColumns      = `id, texts, category, content_types::text[] AS content_types, active, created_at`
+	getPromptQuestionsQuery    = `SELECT ` + promptQuestionColumns + ` FROM profiles.prompt_questions WHERE ($1 = '' OR category = $1) AND (active OR $2) ORDER BY category, created_at`
+	getPromptQuestionByIDQuery = `SELECT ` + promptQuestionColumns + ` FROM profiles.prompt_questions WHERE id = $1`
+	createPromptQuestionQuery  = `INSERT INTO profiles.prompt_questions (id, texts, category, content_types, active)
+							VALUES ($1, $2, $3, $4::text[]::prompt_type[], $5) RETURNING ` + promptQuestionColumns
+	updatePromptQuestionQuery = `UPDATE profiles.prompt_questions SET texts = $2, category = $3, content_types = $4::text[]::prompt_type[], active = $5
+							WHERE id = $1 RETURNING ` + promptQuestionColumns
+	deletePromptQuestionQuery      = `DELETE FROM profiles.prompt_questions WHERE id = $1`
+	isPromptQuestionUsedQuery      = `SELECT EXISTS (SELECT 1 FROM profiles.prompts WHERE question_id = $1)`
+	updatePromptsQuestionTextQuery = `UPDATE profiles.prompts SET question = $2 WHERE question_id = $1`
+
+	// Prompts of the question conflict with other prompts of their user that
+	// would have the same question text and type.
+	isPromptQuestionTextTakenQuery = `SELECT EXISTS (SELECT 1 FROM profiles.prompts pr JOIN profiles.prompts other
+							ON other.user_id = pr.user_id AND other.type = pr.type AND other.id != pr.id
+							WHERE pr.question_id = $1 AND other.question = $2)`
+)
+
+// viewerCondition filters out the profiles p that must be hidden from the viewer passed as $1:
+// suspended and paused profiles, incognito profiles that did not allow the viewer,
+// profiles blocked by the viewer and profiles that blocked the viewer.
+const viewerCondition = `NOT p.suspended AND (p.visibility = 'visible' OR (p.visibility = 'incognito' AND EXISTS (
+	SELECT 1 FROM profiles.allowed_viewers v WHERE v.user_id = p.user_id AND v.viewer_id = $1
+))) AND NOT EXISTS (
+	SELECT 1 FROM profiles.blocks b
+	WHERE (b.blocker_id = $1 AND b.blocked_id = p.user_id) OR (b.blocker_id = p.user_id AND b.blocked_id = $1)
+)`
+
+// promptSearchText is the text of prompts matched by full text search, with
+// the columns qualified by prefix. Only text prompts contribute their content;
+// the content of media prompts is a storage key.
+func promptSearchText(prefix string) string {
+	return prefix + `question || ' ' || CASE WHEN ` + prefix + `type = 'text' THEN ` + prefix + `content ELSE '' END`
+}
+
+// snippetStart and snippetStop mark the matched words in search snippets until
+// the snippets are HTML-escaped.
+const (
+	snippetStart = "\uE000"
+	snippetStop  = "\uE001"
 )
+
+// searchPromptsQuery matches prompts with the full text search index of the
+// language, which is created by the migrations for english and by operators
+// for other languages, see 00016_add_prompts_search_index.up.sql.
+func searchPromptsQuery(language string) string {
+	text := promptSearchText("pr.")
+	vector := `to_tsvector('` + language + `', ` + text + `)`
+	return `SELECT pr.*, ts_headline('` + language + `', ` + text + `, q,
+			'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet
+		FROM profiles.prompts pr JOIN profiles.profiles p ON p.user_id = pr.user_id,
+			websearch_to_tsquery('` + language + `', $2) q
+		WHERE ` + vector + ` @@ q AND (pr.moderation_status = 'approved' OR pr.user_id = $1)
+			AND (p.user_id = $1 OR ` + viewerCondition + `)
+		ORDER BY ts_rank_cd(` + vector + `, q) DESC, pr.id LIMIT $3 OFFSET $4`
+}
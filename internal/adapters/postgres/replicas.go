@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+)
+
+// ReplicaConnection is a connection to a read replica.
+type ReplicaConnection interface {
+	Database
+	Ping(ctx context.Context) error
+}
+
+type replica struct {
+	name    string
+	conn    ReplicaConnection
+	healthy atomic.Bool
+}
+
+func (r *replica) markDown(ctx context.Context, err error) {
+	if r.healthy.Swap(false) {
+		slog.WarnContext(ctx, "database replica is down", "replica", r.name, "error", err)
+		metrics.DBReplicaUp.WithLabelValues(r.name).Set(0)
+	}
+}
+
+func (r *replica) markUp(ctx context.Context) {
+	if !r.healthy.Swap(true) {
+		slog.InfoContext(ctx, "database replica is up", "replica", r.name)
+		metrics.DBReplicaUp.WithLabelValues(r.name).Set(1)
+	}
+}
+
+// replicaSet balances reads over the healthy replicas in round robin.
+type replicaSet struct {
+	replicas []*replica
+	counter  atomic.Uint64
+}
+
+func newReplicaSet(conns []ReplicaConnection) *replicaSet {
+	s := &replicaSet{replicas: make([]*replica, len(conns))}
+	for i, conn := range conns {
+		r := &replica{name: "replica-" + strconv.Itoa(i), conn: conn}
+		r.healthy.Store(true)
+		metrics.DBReplicaUp.WithLabelValues(r.name).Set(1)
+		s.replicas[i] = r
+	}
+	return s
+}
+
+func (s *replicaSet) empty() bool {
+	return len(s.replicas) == 0
+}
+
+// next returns the next healthy replica or nil when all of them are down.
+func (s *replicaSet) next() *replica {
+	n := uint64(len(s.replicas))
+	start := s.counter.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := s.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// MonitorReplicas pings the replicas every interval until the context is
+// done, taking failing replicas out of rotation and restoring recovered ones.
+func (p *Pool) MonitorReplicas(ctx context.Context, interval, timeout time.Duration) {
+	if p.replicas.empty() {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, r := range p.replicas.replicas {
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := r.conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				r.markDown(ctx, err)
+			} else {
+				r.markUp(ctx)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// router sends SELECT statements run outside transactions to a replica and
+// everything else to the primary. Reads fall back to the primary when the
+// replica cannot be reached.
+type router struct {
+	primary  Connection
+	replicas *replicaSet
+}
+
+func (r *router) Begin(ctx context.Context) (pgx.Tx, error) {
+	return r.primary.Begin(ctx)
+}
+
+func (r *router) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, sql, arguments...)
+}
+
+func (r *router) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if isRead(sql) {
+		for rep := r.replicas.next(); rep != nil; rep = r.replicas.next() {
+			rows, err := rep.conn.Query(ctx, sql, args...)
+			if err == nil || !isConnectionError(ctx, err) {
+				metrics.DBReads.WithLabelValues("replica").Inc()
+				return rows, err
+			}
+			rep.markDown(ctx, err)
+		}
+		metrics.DBReads.WithLabelValues("primary").Inc()
+	}
+	return r.primary.Query(ctx, sql, args...)
+}
+
+// QueryRow runs reads with Query, so that they fall back to the primary too.
+func (r *router) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if isRead(sql) {
+		rows, err := r.Query(ctx, sql, args...)
+		return &firstRow{rows: rows, err: err}
+	}
+	return r.primary.QueryRow(ctx, sql, args...)
+}
+
+// firstRow scans the first row of the rows as pgx.Row does.
+type firstRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *firstRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+func isRead(sql string) bool {
+	sql = strings.TrimSpace(sql)
+	return len(sql) >= 6 && strings.EqualFold(sql[:6], "SELECT")
+}
+
+// isConnectionError reports whether the query failed before reaching the
+// server, as opposed to an error returned by the server.
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return !errors.As(err, &pgErr)
+}
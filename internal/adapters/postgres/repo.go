@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,17 +15,51 @@ import (
 )
 
 type Repo struct {
-	pool        ConnPool
-	mapProfiles func(row pgx.CollectableRow) (domain.Profile, error)
-	mapPrompts  func(row pgx.CollectableRow) (domain.Prompt, error)
+	pool         ConnPool
+	searchQuery  string
+	mapProfiles  func(row pgx.CollectableRow) (domain.Profile, error)
+	mapPrompts   func(row pgx.CollectableRow) (domain.Prompt, error)
+	mapBlocks    func(row pgx.CollectableRow) (domain.Block, error)
+	mapViewers   func(row pgx.CollectableRow) (domain.AllowedViewer, error)
+	mapReports   func(row pgx.CollectableRow) (domain.Report, error)
+	mapQuestions func(row pgx.CollectableRow) (domain.PromptQuestion, error)
+	mapImages    func(row pgx.CollectableRow) (domain.GalleryImage, error)
+	mapUploads   func(row pgx.CollectableRow) (domain.MediaUpload, error)
+	mapMatches   func(row pgx.CollectableRow) (domain.PromptMatch, error)
+	mapGalleries func(row pgx.CollectableRow) (promptWithImages, error)
 }
 
-func NewRepo(pool ConnPool) *Repo {
-	return &Repo{
-		pool:        pool,
-		mapProfiles: pgx.RowToStructByName[domain.Profile],
-		mapPrompts:  pgx.RowToStructByName[domain.Prompt],
+// promptWithImages is a prompt with the images of gallery prompts aggregated
+// into a JSON array.
+type promptWithImages struct {
+	domain.Prompt
+	Images []domain.GalleryImage `db:"images"`
+}
+
+// searchLanguagePattern matches the names of text search configurations that
+// can be put in queries without quoting.
+var searchLanguagePattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// NewRepo returns a repository searching prompts with the text search
+// configuration named searchLanguage, such as english or simple.
+func NewRepo(pool ConnPool, searchLanguage string) (*Repo, error) {
+	if !searchLanguagePattern.MatchString(searchLanguage) {
+		return nil, fmt.Errorf("invalid search language %q", searchLanguage)
 	}
+	return &Repo{
+		pool:         pool,
+		searchQuery:  searchPromptsQuery(searchLanguage),
+		mapProfiles:  pgx.RowToStructByName[domain.Profile],
+		mapPrompts:   pgx.RowToStructByName[domain.Prompt],
+		mapBlocks:    pgx.RowToStructByName[domain.Block],
+		mapViewers:   pgx.RowToStructByName[domain.AllowedViewer],
+		mapReports:   pgx.RowToStructByName[domain.Report],
+		mapQuestions: pgx.RowToStructByName[domain.PromptQuestion],
+		mapImages:    pgx.RowToStructByName[domain.GalleryImage],
+		mapUploads:   pgx.RowToStructByName[domain.MediaUpload],
+		mapMatches:   pgx.RowToStructByName[domain.PromptMatch],
+		mapGalleries: pgx.RowToStructByName[promptWithImages],
+	}, nil
 }
 
 func (r *Repo) CreateProfile(ctx context.Context, p *domain.Profile) error {
@@ -32,16 +70,67 @@ func (r *Repo) CreateProfile(ctx context.Context, p *domain.Profile) error {
 		p.HasChildren, p.FamilyPlans, p.Location,
 		p.DrinksAlcohol, p.Smokes,
 	)
-	if _, err := r.pool.GetTx(ctx).Exec(ctx, createProfileQuery, args...); err != nil {
-		return fmt.Errorf("create profile: %w", err)
+	if err := r.pool.GetTx(ctx).QueryRow(ctx, createProfileQuery, args...).Scan(&p.CreatedAt); err != nil {
+		return fmt.Errorf("create profile: %w", translateError(err))
+	}
+	return nil
+}
+
+// UpdateProfileCompleteness computes the completeness score of the profile
+// from its stored prompts and gallery images.
+func (r *Repo) UpdateProfileCompleteness(ctx context.Context, userId uuid.UUID) error {
+	if _, err := r.pool.GetTx(ctx).Exec(ctx, updateProfileCompletenessQuery, userId); err != nil {
+		return fmt.Errorf("update profile completeness: %w", translateError(err))
 	}
 	return nil
 }
 
+// likeEscaper escapes the wildcards of LIKE patterns, so that values are
+// matched literally with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *Repo) ListProfiles(
+	ctx context.Context, filter domain.ProfileFilter, cursor *domain.ProfileCursor, limit int,
+) ([]domain.Profile, error) {
+	var after *time.Time
+	var afterId *uuid.UUID
+	if cursor != nil {
+		after, afterId = &cursor.CreatedAt, &cursor.UserId
+	}
+	rows, err := r.pool.GetTx(ctx).Query(ctx, listProfilesQuery,
+		filter.Sex, filter.MinAge, filter.MaxAge, likeEscaper.Replace(filter.Location), filter.Intention,
+		optionalTime(filter.CreatedAfter), optionalTime(filter.CreatedBefore), filter.HasPhotos,
+		after, afterId, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list profiles: %w", translateError(err))
+	}
+	profiles, err := pgx.CollectRows(rows, r.mapProfiles)
+	if err != nil {
+		return nil, fmt.Errorf("map profiles: %w", err)
+	}
+	return profiles, nil
+}
+
 func (r *Repo) GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profile, error) {
 	rows, err := r.pool.GetTx(ctx).Query(ctx, getProfileByIDQuery, id)
 	if err != nil {
-		return nil, fmt.Errorf("get profile by id: %w", err)
+		return nil, fmt.Errorf("get profile by id: %w", translateError(err))
+	}
+	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (r *Repo) GetProfileForViewer(ctx context.Context, viewerId uuid.UUID, id uuid.UUID) (*domain.Profile, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getProfileForViewerQuery, viewerId, id)
+	if err != nil {
+		return nil, fmt.Errorf("get profile for viewer: %w", translateError(err))
 	}
 	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
 	if err != nil {
@@ -53,10 +142,10 @@ func (r *Repo) GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profil
 	return &profile, nil
 }
 
-func (r *Repo) GetMultipleProfilesByIDs(ctx context.Context, userIds []uuid.UUID) ([]domain.Profile, error) {
-	rows, err := r.pool.GetTx(ctx).Query(ctx, getMultipleProfilesByIDsQuery, userIds)
+func (r *Repo) GetMultipleProfilesByIDs(ctx context.Context, viewerId uuid.UUID, userIds []uuid.UUID) ([]domain.Profile, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getMultipleProfilesByIDsQuery, viewerId, userIds)
 	if err != nil {
-		return nil, fmt.Errorf("get profiles by id: %w", err)
+		return nil, fmt.Errorf("get profiles by id: %w", translateError(err))
 	}
 	prompts, err := pgx.CollectRows(rows, r.mapProfiles)
 	if err != nil {
@@ -66,12 +155,14 @@ func (r *Repo) GetMultipleProfilesByIDs(ctx context.Context, userIds []uuid.UUID
 }
 
 func (r *Repo) GetRandomProfileBySexAndPreference(
-	ctx context.Context, requesterId uuid.UUID, preference domain.Preference, sex string,
+	ctx context.Context, requesterId uuid.UUID, preference domain.Preference, sex string, minPhotos int, minCompleteness int,
 ) (*domain.Profile, error) {
 	pref1, pref2 := preference.Preferences()
-	rows, err := r.pool.GetTx(ctx).Query(ctx, getRandomProfileBySexAndPreferenceQuery, requesterId, pref1, pref2, sex)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getRandomProfileBySexAndPreferenceQuery,
+		requesterId, pref1, pref2, sex, minPhotos, minCompleteness,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("get profile by id: %w", err)
+		return nil, fmt.Errorf("get profile by id: %w", translateError(err))
 	}
 	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
 	if err != nil {
@@ -93,14 +184,14 @@ func (r *Repo) UpdateProfile(ctx context.Context, p domain.Profile) (*domain.Pro
 	)
 	rows, err := r.pool.GetTx(ctx).Query(ctx, updateProfileQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("update profile: %w", err)
+		return nil, fmt.Errorf("update profile: %w", translateError(err))
 	}
 	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, fmt.Errorf("map profile: %w", err)
+		return nil, fmt.Errorf("map profile: %w", translateError(err))
 	}
 	return &profile, nil
 }
@@ -108,7 +199,7 @@ func (r *Repo) UpdateProfile(ctx context.Context, p domain.Profile) (*domain.Pro
 func (r *Repo) GetPromptsByUser(ctx context.Context, userId uuid.UUID) ([]domain.Prompt, error) {
 	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptsByUserQuery, userId)
 	if err != nil {
-		return nil, fmt.Errorf("get prompts by id: %w", err)
+		return nil, fmt.Errorf("get prompts by id: %w", translateError(err))
 	}
 	prompts, err := pgx.CollectRows(rows, r.mapPrompts)
 	if err != nil {
@@ -120,10 +211,11 @@ func (r *Repo) GetPromptsByUser(ctx context.Context, userId uuid.UUID) ([]domain
 func (r *Repo) CreatePrompt(ctx context.Context, prompt domain.Prompt) error {
 	var args []any
 	args = append(args,
-		prompt.ID, prompt.UserId, prompt.Question, prompt.Content, prompt.Type, prompt.Position,
+		prompt.ID, prompt.UserId, prompt.Question, prompt.Content, prompt.Type, prompt.Position, prompt.QuestionId,
+		prompt.ModerationStatus, prompt.DurationMs, prompt.ThumbnailLink, prompt.Renditions,
 	)
 	if _, err := r.pool.GetTx(ctx).Exec(ctx, createPromptQuery, args...); err != nil {
-		return fmt.Errorf("create prompt: %w", err)
+		return fmt.Errorf("create prompt: %w", translateError(err))
 	}
 	return nil
 }
@@ -131,18 +223,19 @@ func (r *Repo) CreatePrompt(ctx context.Context, prompt domain.Prompt) error {
 func (r *Repo) UpdatePromptContent(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error) {
 	var args []any
 	args = append(args,
-		prompt.ID, prompt.Question, prompt.Content, prompt.Position,
+		prompt.ID, prompt.Question, prompt.Content, prompt.Position, prompt.QuestionId, prompt.ModerationStatus,
+		prompt.DurationMs, prompt.ThumbnailLink, prompt.Renditions,
 	)
 	rows, err := r.pool.GetTx(ctx).Query(ctx, updatePromptQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("update prompt: %w", err)
+		return nil, fmt.Errorf("update prompt: %w", translateError(err))
 	}
 	p, err := pgx.CollectOneRow(rows, r.mapPrompts)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, fmt.Errorf("map prompt: %w", err)
+		return nil, fmt.Errorf("map prompt: %w", translateError(err))
 	}
 	return &p, nil
 }
@@ -150,7 +243,7 @@ func (r *Repo) UpdatePromptContent(ctx context.Context, prompt domain.Prompt) (*
 func (r *Repo) GetPromptByID(ctx context.Context, id uuid.UUID) (*domain.Prompt, error) {
 	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptByIDQuery, id)
 	if err != nil {
-		return nil, fmt.Errorf("get prompt by id: %w", err)
+		return nil, fmt.Errorf("get prompt by id: %w", translateError(err))
 	}
 	prompt, err := pgx.CollectOneRow(rows, r.mapPrompts)
 	if err != nil {
@@ -165,7 +258,7 @@ func (r *Repo) GetPromptByID(ctx context.Context, id uuid.UUID) (*domain.Prompt,
 func (r *Repo) GetPromptByUserQuestionAndType(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error) {
 	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptByUserQuestionAndTypeQuery, prompt.UserId, prompt.Question, prompt.Type)
 	if err != nil {
-		return nil, fmt.Errorf("get prompt by id: %w", err)
+		return nil, fmt.Errorf("get prompt by id: %w", translateError(err))
 	}
 	prompt, err = pgx.CollectOneRow(rows, r.mapPrompts)
 	if err != nil {
@@ -184,7 +277,7 @@ func (r *Repo) UpdatePromptsPositions(ctx context.Context, prompts []domain.Prom
 
 	_, err := r.pool.GetTx(ctx).Exec(ctx, updatePromptsPositionQuery, args...)
 	if err != nil {
-		return fmt.Errorf("update prompts position: %w", err)
+		return fmt.Errorf("update prompts position: %w", translateError(err))
 	}
 
 	return nil
@@ -193,7 +286,7 @@ func (r *Repo) UpdatePromptsPositions(ctx context.Context, prompts []domain.Prom
 func (r *Repo) GetPromptsByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Prompt, error) {
 	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptsByIDsQuery, ids)
 	if err != nil {
-		return nil, fmt.Errorf("get prompts by id: %w", err)
+		return nil, fmt.Errorf("get prompts by id: %w", translateError(err))
 	}
 	prompts, err := pgx.CollectRows(rows, r.mapPrompts)
 	if err != nil {
@@ -202,9 +295,478 @@ func (r *Repo) GetPromptsByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.P
 	return prompts, nil
 }
 
+func (r *Repo) GetPromptsByUsers(ctx context.Context, userIds []uuid.UUID) ([]domain.Prompt, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptsByUsersQuery, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("get prompts by users: %w", translateError(err))
+	}
+	rowsWithImages, err := pgx.CollectRows(rows, r.mapGalleries)
+	if err != nil {
+		return nil, fmt.Errorf("map prompts: %w", err)
+	}
+	prompts := make([]domain.Prompt, len(rowsWithImages))
+	for i, row := range rowsWithImages {
+		prompts[i] = row.Prompt
+		prompts[i].Images = row.Images
+	}
+	return prompts, nil
+}
+
+func (r *Repo) SearchPrompts(
+	ctx context.Context, viewerId uuid.UUID, query string, limit, offset int,
+) ([]domain.PromptMatch, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, r.searchQuery, viewerId, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("search prompts: %w", translateError(err))
+	}
+	matches, err := pgx.CollectRows(rows, r.mapMatches)
+	if err != nil {
+		return nil, fmt.Errorf("map prompt matches: %w", err)
+	}
+	for i := range matches {
+		matches[i].Snippet = escapeSnippet(matches[i].Snippet)
+	}
+	return matches, nil
+}
+
+// snippetMarkup replaces the markers of matched words with the markup of
+// escaped snippets.
+var snippetMarkup = strings.NewReplacer(snippetStart, "<b>", snippetStop, "</b>")
+
+// escapeSnippet HTML-escapes the prompt text in the snippet and wraps the
+// matched words in <b></b>.
+func escapeSnippet(snippet string) string {
+	return snippetMarkup.Replace(html.EscapeString(snippet))
+}
+
 func (r *Repo) DeletePrompt(ctx context.Context, id uuid.UUID) error {
 	if _, err := r.pool.GetTx(ctx).Exec(ctx, deletePromptQuery, id); err != nil {
-		return fmt.Errorf("delete prompt: %w", err)
+		return fmt.Errorf("delete prompt: %w", translateDeleteError(err))
+	}
+	return nil
+}
+
+func (r *Repo) CreateBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, createBlockQuery, blockerId, blockedId)
+	if err != nil {
+		return nil, fmt.Errorf("create block: %w", translateError(err))
+	}
+	block, err := pgx.CollectOneRow(rows, r.mapBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("map block: %w", translateError(err))
+	}
+	return &block, nil
+}
+
+func (r *Repo) DeleteBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, deleteBlockQuery, blockerId, blockedId)
+	if err != nil {
+		return nil, fmt.Errorf("delete block: %w", translateDeleteError(err))
+	}
+	block, err := pgx.CollectOneRow(rows, r.mapBlocks)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map block: %w", translateDeleteError(err))
+	}
+	return &block, nil
+}
+
+func (r *Repo) GetBlocksByBlocker(ctx context.Context, blockerId uuid.UUID) ([]domain.Block, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getBlocksByBlockerQuery, blockerId)
+	if err != nil {
+		return nil, fmt.Errorf("get blocks by blocker: %w", translateError(err))
+	}
+	blocks, err := pgx.CollectRows(rows, r.mapBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("map blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+func (r *Repo) UpdateProfileVisibility(ctx context.Context, userId uuid.UUID, visibility domain.Visibility) (*domain.Profile, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, updateProfileVisibilityQuery, userId, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("update profile visibility: %w", translateError(err))
+	}
+	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map profile: %w", translateError(err))
+	}
+	return &profile, nil
+}
+
+func (r *Repo) CreateAllowedViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, createAllowedViewerQuery, userId, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("create allowed viewer: %w", translateError(err))
+	}
+	viewer, err := pgx.CollectOneRow(rows, r.mapViewers)
+	if err != nil {
+		return nil, fmt.Errorf("map allowed viewer: %w", translateError(err))
+	}
+	return &viewer, nil
+}
+
+func (r *Repo) DeleteAllowedViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, deleteAllowedViewerQuery, userId, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("delete allowed viewer: %w", translateDeleteError(err))
+	}
+	viewer, err := pgx.CollectOneRow(rows, r.mapViewers)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map allowed viewer: %w", translateDeleteError(err))
+	}
+	return &viewer, nil
+}
+
+func (r *Repo) GetAllowedViewersByUser(ctx context.Context, userId uuid.UUID) ([]domain.AllowedViewer, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getAllowedViewersByUserQuery, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get allowed viewers by user: %w", translateError(err))
+	}
+	viewers, err := pgx.CollectRows(rows, r.mapViewers)
+	if err != nil {
+		return nil, fmt.Errorf("map allowed viewers: %w", err)
+	}
+	return viewers, nil
+}
+
+func (r *Repo) UpdateProfileSuspended(ctx context.Context, userId uuid.UUID, suspended bool) (*domain.Profile, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, updateProfileSuspendedQuery, userId, suspended)
+	if err != nil {
+		return nil, fmt.Errorf("update profile suspended: %w", translateError(err))
+	}
+	profile, err := pgx.CollectOneRow(rows, r.mapProfiles)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map profile: %w", translateError(err))
+	}
+	return &profile, nil
+}
+
+func (r *Repo) CreateReport(ctx context.Context, report domain.Report) (*domain.Report, error) {
+	var args []any
+	args = append(args,
+		report.ID, report.ReporterId, report.ReportedId, report.Reason, report.PromptId, report.Comment,
+	)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, createReportQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create report: %w", translateError(err))
+	}
+	res, err := pgx.CollectOneRow(rows, r.mapReports)
+	if err != nil {
+		return nil, fmt.Errorf("map report: %w", translateError(err))
+	}
+	return &res, nil
+}
+
+func (r *Repo) GetReportByID(ctx context.Context, id uuid.UUID) (*domain.Report, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getReportByIDQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("get report by id: %w", translateError(err))
+	}
+	report, err := pgx.CollectOneRow(rows, r.mapReports)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map report: %w", err)
+	}
+	return &report, nil
+}
+
+func (r *Repo) GetReports(ctx context.Context, status domain.ReportStatus, limit, offset int) ([]domain.Report, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getReportsQuery, string(status), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get reports: %w", translateError(err))
+	}
+	reports, err := pgx.CollectRows(rows, r.mapReports)
+	if err != nil {
+		return nil, fmt.Errorf("map reports: %w", err)
+	}
+	return reports, nil
+}
+
+func (r *Repo) ClaimReport(ctx context.Context, id uuid.UUID, moderatorId uuid.UUID) (*domain.Report, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, claimReportQuery, id, moderatorId)
+	if err != nil {
+		return nil, fmt.Errorf("claim report: %w", translateError(err))
+	}
+	report, err := pgx.CollectOneRow(rows, r.mapReports)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrReportNotOpen
+		}
+		return nil, fmt.Errorf("map report: %w", translateError(err))
+	}
+	return &report, nil
+}
+
+func (r *Repo) ResolveReport(ctx context.Context, resolution domain.Resolution) (*domain.Report, error) {
+	var args []any
+	args = append(args,
+		resolution.ReportId, resolution.ModeratorId, resolution.Action, resolution.Note,
+	)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, resolveReportQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("resolve report: %w", translateError(err))
+	}
+	report, err := pgx.CollectOneRow(rows, r.mapReports)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrReportNotClaimed
+		}
+		return nil, fmt.Errorf("map report: %w", translateError(err))
+	}
+	return &report, nil
+}
+
+func (r *Repo) GetPromptQuestions(ctx context.Context, category string, includeInactive bool) ([]domain.PromptQuestion, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptQuestionsQuery, category, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt questions: %w", translateError(err))
+	}
+	questions, err := pgx.CollectRows(rows, r.mapQuestions)
+	if err != nil {
+		return nil, fmt.Errorf("map prompt questions: %w", err)
+	}
+	return questions, nil
+}
+
+func (r *Repo) GetPromptQuestionByID(ctx context.Context, id uuid.UUID) (*domain.PromptQuestion, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getPromptQuestionByIDQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt question by id: %w", translateError(err))
+	}
+	question, err := pgx.CollectOneRow(rows, r.mapQuestions)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map prompt question: %w", err)
+	}
+	return &question, nil
+}
+
+func (r *Repo) CreatePromptQuestion(ctx context.Context, q domain.PromptQuestion) (*domain.PromptQuestion, error) {
+	var args []any
+	args = append(args,
+		q.ID, q.Texts, q.Category, contentTypesToStrings(q.ContentTypes), q.Active,
+	)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, createPromptQuestionQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create prompt question: %w", translateError(err))
+	}
+	question, err := pgx.CollectOneRow(rows, r.mapQuestions)
+	if err != nil {
+		return nil, fmt.Errorf("map prompt question: %w", translateError(err))
+	}
+	return &question, nil
+}
+
+func (r *Repo) UpdatePromptQuestion(ctx context.Context, q domain.PromptQuestion) (*domain.PromptQuestion, error) {
+	var args []any
+	args = append(args,
+		q.ID, q.Texts, q.Category, contentTypesToStrings(q.ContentTypes), q.Active,
+	)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, updatePromptQuestionQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("update prompt question: %w", translateError(err))
+	}
+	question, err := pgx.CollectOneRow(rows, r.mapQuestions)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map prompt question: %w", translateError(err))
+	}
+	return &question, nil
+}
+
+func (r *Repo) DeletePromptQuestion(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.GetTx(ctx).Exec(ctx, deletePromptQuestionQuery, id); err != nil {
+		return fmt.Errorf("delete prompt question: %w", translateDeleteError(err))
+	}
+	return nil
+}
+
+func (r *Repo) IsPromptQuestionUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	var used bool
+	if err := r.pool.GetTx(ctx).QueryRow(ctx, isPromptQuestionUsedQuery, id).Scan(&used); err != nil {
+		return false, fmt.Errorf("check prompt question usage: %w", translateError(err))
+	}
+	return used, nil
+}
+
+// IsPromptQuestionTextTaken reports whether changing the text of the prompts
+// of the question would make them collide with other prompts of their users.
+func (r *Repo) IsPromptQuestionTextTaken(ctx context.Context, questionId uuid.UUID, text string) (bool, error) {
+	var taken bool
+	if err := r.pool.GetTx(ctx).QueryRow(ctx, isPromptQuestionTextTakenQuery, questionId, text).Scan(&taken); err != nil {
+		return false, fmt.Errorf("check prompt question text: %w", translateError(err))
+	}
+	return taken, nil
+}
+
+func (r *Repo) UpdatePromptsQuestionText(ctx context.Context, questionId uuid.UUID, text string) error {
+	if _, err := r.pool.GetTx(ctx).Exec(ctx, updatePromptsQuestionTextQuery, questionId, text); err != nil {
+		return fmt.Errorf("update prompts question text: %w", translateError(err))
+	}
+	return nil
+}
+
+// UpdatePromptModerationStatus is a no-op when the prompt content changed
+// after it was moderated.
+func (r *Repo) UpdatePromptModerationStatus(ctx context.Context, prompt domain.Prompt) error {
+	_, err := r.pool.GetTx(ctx).Exec(ctx, updatePromptModerationStatusQuery,
+		prompt.ID, prompt.ModerationStatus, prompt.Question, prompt.Content,
+	)
+	if err != nil {
+		return fmt.Errorf("update prompt moderation status: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *Repo) GetGalleryImagesByPrompts(ctx context.Context, promptIds []uuid.UUID) ([]domain.GalleryImage, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getGalleryImagesByPromptsQuery, promptIds)
+	if err != nil {
+		return nil, fmt.Errorf("get gallery images: %w", translateError(err))
+	}
+	images, err := pgx.CollectRows(rows, r.mapImages)
+	if err != nil {
+		return nil, fmt.Errorf("map gallery images: %w", err)
+	}
+	return images, nil
+}
+
+func (r *Repo) CreateGalleryImage(ctx context.Context, image domain.GalleryImage) (*domain.GalleryImage, error) {
+	var args []any
+	args = append(args,
+		image.ID, image.PromptId, image.Link, image.Caption, image.Position, image.ModerationStatus, image.Renditions,
+	)
+	rows, err := r.pool.GetTx(ctx).Query(ctx, createGalleryImageQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create gallery image: %w", translateError(err))
+	}
+	res, err := pgx.CollectOneRow(rows, r.mapImages)
+	if err != nil {
+		return nil, fmt.Errorf("map gallery image: %w", translateError(err))
+	}
+	return &res, nil
+}
+
+func (r *Repo) DeleteGalleryImage(ctx context.Context, promptId uuid.UUID, id uuid.UUID) (*domain.GalleryImage, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, deleteGalleryImageQuery, id, promptId)
+	if err != nil {
+		return nil, fmt.Errorf("delete gallery image: %w", translateDeleteError(err))
+	}
+	image, err := pgx.CollectOneRow(rows, r.mapImages)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map gallery image: %w", translateDeleteError(err))
+	}
+	return &image, nil
+}
+
+func (r *Repo) UpdateGalleryImagesPositions(ctx context.Context, images []domain.GalleryImage) error {
+	batch := NewGalleryImageBatch(images)
+	_, err := r.pool.GetTx(ctx).Exec(ctx, updateGalleryImagesPositionQuery, batch.IDs, batch.Positions)
+	if err != nil {
+		return fmt.Errorf("update gallery images position: %w", translateError(err))
 	}
 	return nil
 }
+
+func (r *Repo) UpdateGalleryImageModerationStatus(ctx context.Context, image domain.GalleryImage) error {
+	_, err := r.pool.GetTx(ctx).Exec(ctx, updateGalleryImageModerationStatusQuery, image.ID, image.ModerationStatus)
+	if err != nil {
+		return fmt.Errorf("update gallery image moderation status: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *Repo) GetMediaUpload(ctx context.Context, userId uuid.UUID, sha256 []byte) (*domain.MediaUpload, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, getMediaUploadQuery, userId, sha256)
+	if err != nil {
+		return nil, fmt.Errorf("get media upload: %w", translateError(err))
+	}
+	upload, err := pgx.CollectOneRow(rows, r.mapUploads)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("map media upload: %w", err)
+	}
+	return &upload, nil
+}
+
+func (r *Repo) CreateMediaUpload(ctx context.Context, upload domain.MediaUpload) error {
+	_, err := r.pool.GetTx(ctx).Exec(ctx, createMediaUploadQuery,
+		upload.UserId, upload.Sha256, upload.PerceptualHash, upload.Link, upload.Renditions,
+	)
+	if err != nil {
+		return fmt.Errorf("create media upload: %w", translateError(err))
+	}
+	return nil
+}
+
+// MaxDuplicateDistance is the largest perceptual hash distance
+// FindSimilarUploads finds all the uploads within. Hashes are split in one
+// more band than that, so that close hashes share at least one of them.
+const MaxDuplicateDistance = 4
+
+// FindSameUploads returns uploads of other users with the same content.
+func (r *Repo) FindSameUploads(ctx context.Context, upload domain.MediaUpload, limit int) ([]domain.MediaUpload, error) {
+	rows, err := r.pool.GetTx(ctx).Query(ctx, findSameUploadsQuery, upload.UserId, upload.Sha256, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find same uploads: %w", translateError(err))
+	}
+	uploads, err := pgx.CollectRows(rows, r.mapUploads)
+	if err != nil {
+		return nil, fmt.Errorf("map media uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// FindSimilarUploads returns uploads of other users with a perceptual hash
+// within maxDistance bits of the upload's one, which must be at most
+// MaxDuplicateDistance.
+func (r *Repo) FindSimilarUploads(
+	ctx context.Context, upload domain.MediaUpload, maxDistance int, limit int,
+) ([]domain.MediaUpload, error) {
+	if upload.PerceptualHash == nil {
+		return nil, nil
+	}
+	rows, err := r.pool.GetTx(ctx).Query(ctx, findSimilarUploadsQuery,
+		upload.UserId, *upload.PerceptualHash, maxDistance, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find similar uploads: %w", translateError(err))
+	}
+	uploads, err := pgx.CollectRows(rows, r.mapUploads)
+	if err != nil {
+		return nil, fmt.Errorf("map media uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+func (r *Repo) HasOpenReport(ctx context.Context, reportedId uuid.UUID, reason domain.ReportReason) (bool, error) {
+	var exists bool
+	err := r.pool.GetTx(ctx).QueryRow(ctx, hasOpenReportQuery, reportedId, reason).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check open reports: %w", translateError(err))
+	}
+	return exists, nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import "testing"
+
+func TestEscapeSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "plain text",
+			snippet: "I love hiking",
+			want:    "I love hiking",
+		},
+		{
+			name:    "matched words",
+			snippet: "I love " + snippetStart + "hiking" + snippetStop + " in the alps",
+			want:    "I love <b>hiking</b> in the alps",
+		},
+		{
+			name:    "markup in the prompt",
+			snippet: `<script>alert("x")</script> & ` + snippetStart + "hiking" + snippetStop,
+			want:    "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; <b>hiking</b>",
+		},
+		{
+			name:    "markup in a matched word",
+			snippet: snippetStart + "<b>" + snippetStop,
+			want:    "<b>&lt;b&gt;</b>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeSnippet(tt.snippet); got != tt.want {
+				t.Errorf("escapeSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRepoSearchLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		wantErr  bool
+	}{
+		{language: "english"},
+		{language: "simple"},
+		{language: "", wantErr: true},
+		{language: "English", wantErr: true},
+		{language: "english'); DROP TABLE profiles.prompts; --", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			_, err := NewRepo(nil, tt.language)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRepo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLikeEscaper(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Berlin", want: "Berlin"},
+		{value: "100%", want: `100\%`},
+		{value: "new_york", want: `new\_york`},
+		{value: `a\b`, want: `a\\b`},
+		{value: `\%_`, want: `\\\%\_`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := likeEscaper.Replace(tt.value); got != tt.want {
+				t.Errorf("likeEscaper.Replace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
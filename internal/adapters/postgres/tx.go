@@ -0,0 +1,49 @@
+package postgres
+
+type IsolationLevel string
+
+const (
+	ReadCommitted  IsolationLevel = "read committed"
+	RepeatableRead IsolationLevel = "repeatable read"
+	Serializable   IsolationLevel = "serializable"
+)
+
+// TxOptions configure a transaction. The zero value is a read-write
+// transaction with the database default isolation level.
+type TxOptions struct {
+	Isolation  IsolationLevel
+	ReadOnly   bool
+	Deferrable bool
+	// OnPrimary keeps read-only transactions on the primary, for reads
+	// that must see the latest writes.
+	OnPrimary bool
+}
+
+type TxOption func(*TxOptions)
+
+func NewTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) { o.Isolation = level }
+}
+
+// ReadOnly transactions may run on a replica lagging behind the primary.
+func ReadOnly() TxOption {
+	return func(o *TxOptions) { o.ReadOnly = true }
+}
+
+// Deferrable makes serializable read-only transactions wait for a snapshot
+// that cannot fail with a serialization error.
+func Deferrable() TxOption {
+	return func(o *TxOptions) { o.Deferrable = true }
+}
+
+func OnPrimary() TxOption {
+	return func(o *TxOptions) { o.OnPrimary = true }
+}
@@ -2,44 +2,88 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/samber/lo"
-	"log"
-	"sort"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/soulmate-dating/profiles/internal/adapters/cache"
 	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
 	"github.com/soulmate-dating/profiles/internal/app/clients/media"
 	"github.com/soulmate-dating/profiles/internal/config"
 	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/imaging"
+	"github.com/soulmate-dating/profiles/internal/mediainfo"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+	"github.com/soulmate-dating/profiles/internal/moderation"
 )
 
 type App interface {
 	CreateProfile(ctx context.Context, profile *domain.Profile) (*domain.Profile, error)
-	GetProfile(ctx context.Context, userId uuid.UUID) (*domain.Profile, error)
+	GetProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (*domain.Profile, error)
 	UpdateProfile(ctx context.Context, profile domain.Profile) (*domain.Profile, error)
 	GetRandomProfilePreferredByUser(ctx context.Context, userId uuid.UUID) (*domain.FullProfile, error)
-	GetFullProfile(ctx context.Context, userId uuid.UUID) (*domain.FullProfile, error)
+	GetFullProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (*domain.FullProfile, error)
 
-	GetPrompts(ctx context.Context, userId uuid.UUID) ([]domain.Prompt, error)
+	GetPrompts(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) ([]domain.Prompt, error)
+	SearchPrompts(ctx context.Context, viewerId uuid.UUID, query string, limit, offset int) (*domain.PromptSearchResult, error)
 	AddPrompts(ctx context.Context, prompts []domain.Prompt) ([]domain.Prompt, error)
 	UpdatePrompt(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error)
 	UpdatePromptsPositions(ctx context.Context, prompts []domain.Prompt) ([]domain.Prompt, error)
-	GetMultipleProfiles(ctx context.Context, ids []uuid.UUID) ([]domain.Profile, error)
+	GetMultipleProfiles(ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID) ([]domain.Profile, error)
+	GetMultipleFullProfiles(ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID) (*domain.FullProfiles, error)
 	AddFilePrompt(ctx context.Context, prompt domain.FilePrompt) (*domain.Prompt, error)
 	UpdateFilePrompt(ctx context.Context, prompt domain.FilePrompt) (*domain.Prompt, error)
 	DeletePrompt(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Prompt, error)
+
+	AddGalleryImage(ctx context.Context, upload domain.GalleryUpload) (*domain.Prompt, error)
+	RemoveGalleryImage(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageId uuid.UUID) (*domain.Prompt, error)
+	ReorderGalleryImages(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageIds []uuid.UUID) (*domain.Prompt, error)
+
+	BlockUser(ctx context.Context, userId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error)
+	UnblockUser(ctx context.Context, userId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error)
+	ListBlockedUsers(ctx context.Context, userId uuid.UUID) ([]domain.Block, error)
+
+	SetVisibility(ctx context.Context, userId uuid.UUID, visibility domain.Visibility) (*domain.Profile, error)
+	AllowViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error)
+	DisallowViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error)
+	ListAllowedViewers(ctx context.Context, userId uuid.UUID) ([]domain.AllowedViewer, error)
+
+	SetMainPicture(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Profile, error)
+
+	ReportProfile(ctx context.Context, report domain.Report) (*domain.Report, error)
+	ListReports(ctx context.Context, status domain.ReportStatus, limit, offset int) ([]domain.Report, error)
+	ClaimReport(ctx context.Context, reportId uuid.UUID, moderatorId uuid.UUID) (*domain.Report, error)
+	ResolveReport(ctx context.Context, resolution domain.Resolution) (*domain.Report, error)
+	ListProfiles(ctx context.Context, filter domain.ProfileFilter, cursor *domain.ProfileCursor, limit int) (*domain.ProfilePage, error)
+
+	ListPromptQuestions(ctx context.Context, category string, includeInactive bool) ([]domain.PromptQuestion, error)
+	CreatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (*domain.PromptQuestion, error)
+	UpdatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (*domain.PromptQuestion, error)
+	DeletePromptQuestion(ctx context.Context, id uuid.UUID) (*domain.PromptQuestion, error)
+
+	// Shutdown waits for the content moderated in the background until the
+	// context ends.
+	Shutdown(ctx context.Context) error
 }
 
 type Repository interface {
 	CreateProfile(ctx context.Context, p *domain.Profile) error
 	GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profile, error)
 	UpdateProfile(ctx context.Context, profile domain.Profile) (*domain.Profile, error)
-	GetMultipleProfilesByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Profile, error)
+	GetProfileForViewer(ctx context.Context, viewerId uuid.UUID, id uuid.UUID) (*domain.Profile, error)
+	GetMultipleProfilesByIDs(ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID) ([]domain.Profile, error)
 	GetRandomProfileBySexAndPreference(
-		ctx context.Context, requesterId uuid.UUID, preference domain.Preference, sex string,
+		ctx context.Context, requesterId uuid.UUID, preference domain.Preference, sex string, minPhotos int, minCompleteness int,
 	) (*domain.Profile, error)
+	UpdateProfileCompleteness(ctx context.Context, userId uuid.UUID) error
 
 	GetPromptsByUser(ctx context.Context, userId uuid.UUID) ([]domain.Prompt, error)
 	GetPromptByID(ctx context.Context, id uuid.UUID) (*domain.Prompt, error)
@@ -48,18 +92,76 @@ type Repository interface {
 	UpdatePromptContent(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error)
 	UpdatePromptsPositions(ctx context.Context, prompts []domain.Prompt) error
 	GetPromptsByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Prompt, error)
+	// GetPromptsByUsers returns the prompts of the users with the images of
+	// gallery prompts.
+	GetPromptsByUsers(ctx context.Context, userIds []uuid.UUID) ([]domain.Prompt, error)
+	SearchPrompts(ctx context.Context, viewerId uuid.UUID, query string, limit, offset int) ([]domain.PromptMatch, error)
 	DeletePrompt(ctx context.Context, id uuid.UUID) error
+
+	GetGalleryImagesByPrompts(ctx context.Context, promptIds []uuid.UUID) ([]domain.GalleryImage, error)
+	CreateGalleryImage(ctx context.Context, image domain.GalleryImage) (*domain.GalleryImage, error)
+	DeleteGalleryImage(ctx context.Context, promptId uuid.UUID, id uuid.UUID) (*domain.GalleryImage, error)
+	UpdateGalleryImagesPositions(ctx context.Context, images []domain.GalleryImage) error
+	UpdateGalleryImageModerationStatus(ctx context.Context, image domain.GalleryImage) error
+
+	GetMediaUpload(ctx context.Context, userId uuid.UUID, sha256 []byte) (*domain.MediaUpload, error)
+	CreateMediaUpload(ctx context.Context, upload domain.MediaUpload) error
+	FindSameUploads(ctx context.Context, upload domain.MediaUpload, limit int) ([]domain.MediaUpload, error)
+	FindSimilarUploads(ctx context.Context, upload domain.MediaUpload, maxDistance int, limit int) ([]domain.MediaUpload, error)
+	HasOpenReport(ctx context.Context, reportedId uuid.UUID, reason domain.ReportReason) (bool, error)
+
+	CreateBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error)
+	DeleteBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error)
+	GetBlocksByBlocker(ctx context.Context, blockerId uuid.UUID) ([]domain.Block, error)
+
+	UpdateProfileVisibility(ctx context.Context, userId uuid.UUID, visibility domain.Visibility) (*domain.Profile, error)
+	CreateAllowedViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error)
+	DeleteAllowedViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error)
+	GetAllowedViewersByUser(ctx context.Context, userId uuid.UUID) ([]domain.AllowedViewer, error)
+
+	UpdateProfileSuspended(ctx context.Context, userId uuid.UUID, suspended bool) (*domain.Profile, error)
+	CreateReport(ctx context.Context, report domain.Report) (*domain.Report, error)
+	GetReportByID(ctx context.Context, id uuid.UUID) (*domain.Report, error)
+	GetReports(ctx context.Context, status domain.ReportStatus, limit, offset int) ([]domain.Report, error)
+	ClaimReport(ctx context.Context, id uuid.UUID, moderatorId uuid.UUID) (*domain.Report, error)
+	ResolveReport(ctx context.Context, resolution domain.Resolution) (*domain.Report, error)
+	ListProfiles(ctx context.Context, filter domain.ProfileFilter, cursor *domain.ProfileCursor, limit int) ([]domain.Profile, error)
+
+	GetPromptQuestions(ctx context.Context, category string, includeInactive bool) ([]domain.PromptQuestion, error)
+	GetPromptQuestionByID(ctx context.Context, id uuid.UUID) (*domain.PromptQuestion, error)
+	CreatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (*domain.PromptQuestion, error)
+	UpdatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (*domain.PromptQuestion, error)
+	DeletePromptQuestion(ctx context.Context, id uuid.UUID) error
+	IsPromptQuestionUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	IsPromptQuestionTextTaken(ctx context.Context, questionId uuid.UUID, text string) (bool, error)
+	UpdatePromptsQuestionText(ctx context.Context, questionId uuid.UUID, text string) error
+
+	UpdatePromptModerationStatus(ctx context.Context, prompt domain.Prompt) error
 }
 
 type TransactionManager interface {
-	RunInTx(ctx context.Context, f func(ctx context.Context) error) error
+	RunInTx(ctx context.Context, f func(ctx context.Context) error, opts ...postgres.TxOption) error
 }
 
 type Application struct {
-	validate    *validator.Validate
-	txManager   TransactionManager
-	repository  Repository
-	mediaClient media.MediaServiceClient
+	validate         *validator.Validate
+	txManager        TransactionManager
+	repository       Repository
+	mediaClient      media.MediaServiceClient
+	promptLimits     domain.PromptLimits
+	minPhotos        int
+	minCompleteness  int
+	maxGalleryImages int
+	maxFullProfiles  int
+
+	moderator         moderation.Moderator
+	moderationMode    moderation.Mode
+	moderationTimeout time.Duration
+	moderating        sync.WaitGroup
+	duplicateDistance int
+	mediaLimits       domain.MediaLimits
+	renditionSizes    []imaging.Size
+	maxImagePixels    int
 }
 
 func (a *Application) DeletePrompt(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (p *domain.Prompt, err error) {
@@ -74,14 +176,6 @@ func (a *Application) DeletePrompt(ctx context.Context, userId uuid.UUID, prompt
 }
 
 func (a *Application) deletePrompt(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Prompt, error) {
-	p, err := a.repository.GetProfileByID(ctx, userId)
-	if err != nil {
-		return nil, fmt.Errorf("get profile: %w", err)
-	}
-	if p.MainPicPromptID != nil && *p.MainPicPromptID == promptId {
-		return nil, domain.ErrCannotDeleteProfilePic
-	}
-
 	prompt, err := a.repository.GetPromptByID(ctx, promptId)
 	if err != nil {
 		return nil, fmt.Errorf("get prompt: %w", err)
@@ -89,11 +183,23 @@ func (a *Application) deletePrompt(ctx context.Context, userId uuid.UUID, prompt
 	if prompt.UserId.String() != userId.String() {
 		return nil, domain.ErrForbidden
 	}
+	err = a.replaceMainPicture(ctx, userId, promptId)
+	if err != nil {
+		return nil, err
+	}
 
 	err = a.repository.DeletePrompt(ctx, promptId)
 	if err != nil {
 		return nil, fmt.Errorf("delete prompt: %w", err)
 	}
+	err = a.compactPrompts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	err = a.refreshCompleteness(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
 
 	return prompt, nil
 }
@@ -103,29 +209,25 @@ func (a *Application) UpdateFilePrompt(ctx context.Context, filePrompt domain.Fi
 	if err != nil {
 		return nil, fmt.Errorf("invalid file prompt: %w", err)
 	}
-	response, err := a.mediaClient.UploadFile(ctx, &media.UploadFileRequest{
-		ContentType: "image/png",
-		Data:        filePrompt.Content,
-	})
+	prompt, info, err := a.prepareFilePrompt(ctx, filePrompt)
 	if err != nil {
 		return nil, err
 	}
-
-	prompt := domain.Prompt{
-		ID:       filePrompt.ID,
-		UserId:   filePrompt.UserId,
-		Question: filePrompt.Question,
-		Content:  response.GetLink(),
-		Position: filePrompt.Position,
-		Type:     filePrompt.Type,
+	err = a.uploadFilePrompt(ctx, &prompt, filePrompt, info.MimeType)
+	if err != nil {
+		return nil, err
 	}
+
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
 		res, err = a.updatePrompt(ctx, prompt)
 		if err != nil {
 			return fmt.Errorf("failed to update prompt: %w", err)
 		}
 		return nil
-	})
+	}, postgres.WithIsolation(postgres.Serializable))
+	if err == nil {
+		a.moderateInBackground(ctx, []domain.Prompt{*res}, moderatedImage(filePrompt))
+	}
 	return res, err
 }
 
@@ -134,47 +236,128 @@ func (a *Application) AddFilePrompt(ctx context.Context, filePrompt domain.FileP
 	if err != nil {
 		return nil, fmt.Errorf("invalid file prompt: %w", err)
 	}
+	p, info, err := a.prepareFilePrompt(ctx, filePrompt)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = domain.NewUID()
+	// A replica may not have the profile created just before.
+	err = a.checkProfileExists(postgres.WithPrimary(ctx), filePrompt.UserId)
+	if err != nil {
+		return nil, err
+	}
+	// Uploads are not repeated when the transaction is retried.
+	err = a.uploadFilePrompt(ctx, &p, filePrompt, info.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		prompt, err = a.addFilePrompt(ctx, filePrompt)
+		prompt, err = a.addFilePrompt(ctx, p, filePrompt)
 		if err != nil {
 			return fmt.Errorf("failed to add file prompt: %w", err)
 		}
 		return nil
-	})
+	}, postgres.WithIsolation(postgres.Serializable))
+	if err == nil {
+		countAddedPrompts(*prompt)
+		a.moderateInBackground(ctx, []domain.Prompt{*prompt}, moderatedImage(filePrompt))
+	}
 	return prompt, err
 }
 
-func (a *Application) addFilePrompt(ctx context.Context, filePrompt domain.FilePrompt) (*domain.Prompt, error) {
-	_, err := a.repository.GetProfileByID(ctx, filePrompt.UserId)
+func (a *Application) addFilePrompt(ctx context.Context, prompt domain.Prompt, filePrompt domain.FilePrompt) (*domain.Prompt, error) {
+	err := a.checkProfileExists(ctx, filePrompt.UserId)
 	if err != nil {
-		return nil, domain.ErrAddPromptsOnEmptyProfile
+		return nil, err
 	}
-	response, err := a.mediaClient.UploadFile(ctx, &media.UploadFileRequest{
-		ContentType: "image/png",
-		Data:        filePrompt.Content,
-	})
+
+	prompts, err := a.insertPrompts(ctx, filePrompt.UserId, []domain.Prompt{prompt})
 	if err != nil {
 		return nil, err
 	}
+	return &prompts[0], nil
+}
 
+// prepareFilePrompt builds the prompt stored for the file prompt, checking
+// audio and video files against the media limits and moderating the content.
+// The files are uploaded separately by uploadFilePrompt.
+func (a *Application) prepareFilePrompt(ctx context.Context, filePrompt domain.FilePrompt) (domain.Prompt, domain.MediaInfo, error) {
 	prompt := domain.Prompt{
-		ID:       domain.NewUID(),
-		UserId:   filePrompt.UserId,
-		Question: filePrompt.Question,
-		Content:  response.GetLink(),
-		Position: filePrompt.Position,
-		Type:     filePrompt.Type,
+		ID:         filePrompt.ID,
+		UserId:     filePrompt.UserId,
+		Question:   filePrompt.Question,
+		Position:   filePrompt.Position,
+		Type:       filePrompt.Type,
+		QuestionId: filePrompt.QuestionId,
+	}
+	var info domain.MediaInfo
+	var err error
+	if filePrompt.Type.IsMedia() {
+		info, err = mediainfo.Probe(filePrompt.Content)
+		if err != nil {
+			return domain.Prompt{}, info, fmt.Errorf("%w: %w", domain.ErrUnsupportedMedia, err)
+		}
+		err = a.mediaLimits.Check(filePrompt.Type, info)
+		if err != nil {
+			return domain.Prompt{}, info, err
+		}
+		prompt.DurationMs = int32(info.Duration.Milliseconds())
+	} else {
+		info.MimeType, err = imaging.MimeType(filePrompt.Content)
+		if err != nil {
+			return domain.Prompt{}, info, fmt.Errorf("%w: %w", domain.ErrUnsupportedMedia, err)
+		}
 	}
-	err = a.addPrompt(ctx, prompt)
+
+	status, err := a.checkPrompt(ctx, prompt, moderatedImage(filePrompt))
 	if err != nil {
-		return nil, err
+		return domain.Prompt{}, info, fmt.Errorf("moderate prompt: %w", err)
 	}
-	return &prompt, err
+	prompt.ModerationStatus = status
+	return prompt, info, nil
 }
 
-func (a *Application) GetFullProfile(ctx context.Context, userId uuid.UUID) (profile *domain.FullProfile, err error) {
-	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		profile, err = a.getFullProfile(ctx, userId)
+func (a *Application) uploadFilePrompt(ctx context.Context, prompt *domain.Prompt, filePrompt domain.FilePrompt, mimeType string) error {
+	var thumbnailType string
+	if filePrompt.Type == domain.Video && len(filePrompt.Thumbnail) > 0 {
+		var err error
+		thumbnailType, err = imaging.MimeType(filePrompt.Thumbnail)
+		if err != nil {
+			return fmt.Errorf("thumbnail: %w: %w", domain.ErrUnsupportedMedia, err)
+		}
+	}
+	file, err := a.uploadFile(ctx, filePrompt.UserId, filePrompt.Type, mimeType, filePrompt.Content)
+	if err != nil {
+		return err
+	}
+	prompt.Content, prompt.Renditions = file.Link, file.Renditions
+	if filePrompt.Type == domain.Video && len(filePrompt.Thumbnail) > 0 {
+		// Thumbnails are uploaded as part of the video, without renditions.
+		thumbnail, err := a.uploadFile(ctx, filePrompt.UserId, domain.Video, thumbnailType, filePrompt.Thumbnail)
+		if err != nil {
+			return err
+		}
+		prompt.ThumbnailLink = thumbnail.Link
+	}
+	return nil
+}
+
+// moderatedImage returns the image checked by the image moderators: the
+// picture of an image prompt or the thumbnail of a video prompt.
+func moderatedImage(filePrompt domain.FilePrompt) []byte {
+	switch filePrompt.Type {
+	case domain.Image:
+		return filePrompt.Content
+	case domain.Video:
+		return filePrompt.Thumbnail
+	}
+	return nil
+}
+
+func (a *Application) GetFullProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (profile *domain.FullProfile, err error) {
+	err = a.runInReadTx(ctx, viewerId, userId, func(ctx context.Context) error {
+		profile, err = a.getFullProfile(ctx, viewerId, userId)
 		if err != nil {
 			return fmt.Errorf("failed to get full profile: %w", err)
 		}
@@ -183,8 +366,8 @@ func (a *Application) GetFullProfile(ctx context.Context, userId uuid.UUID) (pro
 	return profile, err
 }
 
-func (a *Application) getFullProfile(ctx context.Context, userId uuid.UUID) (*domain.FullProfile, error) {
-	profile, err := a.getProfile(ctx, userId)
+func (a *Application) getFullProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (*domain.FullProfile, error) {
+	profile, err := a.getProfile(ctx, viewerId, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -193,73 +376,109 @@ func (a *Application) getFullProfile(ctx context.Context, userId uuid.UUID) (*do
 	if err != nil {
 		return nil, fmt.Errorf("get prompts: %w", err)
 	}
+	err = a.attachGalleryImages(ctx, viewerId, prompts)
+	if err != nil {
+		return nil, err
+	}
 
-	return &domain.FullProfile{
+	fullProfile := &domain.FullProfile{
 		Profile: *profile,
-		Prompts: prompts,
-	}, nil
+		Prompts: visiblePrompts(viewerId, prompts),
+	}
+	// The owner sees all their prompts, which the score is computed from.
+	if viewerId == userId {
+		completeness := domain.NewCompleteness(*profile, prompts)
+		fullProfile.Completeness = &completeness
+	}
+	return fullProfile, nil
 }
 
 func (a *Application) GetRandomProfilePreferredByUser(ctx context.Context, userId uuid.UUID) (profile *domain.FullProfile, err error) {
+	var empty bool
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		profile, err = a.getRandomProfilePreferredByUser(ctx, userId)
+		profile, empty, err = a.getRandomProfilePreferredByUser(ctx, userId)
 		if err != nil {
 			return fmt.Errorf("failed to get recommendation: %w", err)
 		}
 		return nil
-	})
+	}, postgres.ReadOnly(), postgres.OnPrimary())
+	// Recommendations are counted once, after the transaction is no longer retried.
+	switch {
+	case err == nil:
+		metrics.Recommendations.WithLabelValues("found").Inc()
+	case empty:
+		metrics.Recommendations.WithLabelValues("empty").Inc()
+	}
 	return profile, err
 }
 
-func (a *Application) getRandomProfilePreferredByUser(ctx context.Context, userId uuid.UUID) (*domain.FullProfile, error) {
+// getRandomProfilePreferredByUser returns a random profile matching the
+// preferences of the user. empty reports whether no profile matched.
+func (a *Application) getRandomProfilePreferredByUser(ctx context.Context, userId uuid.UUID) (_ *domain.FullProfile, empty bool, err error) {
 	profile, err := a.repository.GetProfileByID(ctx, userId)
 	if err != nil {
-		return nil, fmt.Errorf("get profile: %w", err)
+		return nil, false, fmt.Errorf("get profile: %w", err)
 	}
 
 	p, err := a.repository.GetRandomProfileBySexAndPreference(
-		ctx, profile.UserId, domain.Preference(profile.PreferredPartner), profile.Sex,
+		ctx, profile.UserId, domain.Preference(profile.PreferredPartner), profile.Sex, a.minPhotos, a.minCompleteness,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get recommedation: %w", err)
+		return nil, errors.Is(err, domain.ErrNotFound), fmt.Errorf("get recommedation: %w", err)
 	}
 	if p.MainPicPromptID != nil {
 		prompt, err := a.repository.GetPromptByID(ctx, *p.MainPicPromptID)
 		if err != nil {
-			return nil, fmt.Errorf("get prompt for profile pic: %w", err)
+			return nil, false, fmt.Errorf("get prompt for profile pic: %w", err)
+		}
+		if prompt.VisibleTo(userId) {
+			p.MainPicLink, p.MainPicRenditions = prompt.Content, prompt.Renditions
 		}
-		p.MainPicLink = prompt.Content
 	}
 
 	prompts, err := a.repository.GetPromptsByUser(ctx, p.UserId)
 	if err != nil {
-		return nil, fmt.Errorf("get prompt for recommended profile: %w", err)
+		return nil, false, fmt.Errorf("get prompt for recommended profile: %w", err)
+	}
+	err = a.attachGalleryImages(ctx, userId, prompts)
+	if err != nil {
+		return nil, false, err
 	}
 
 	return &domain.FullProfile{
 		Profile: *p,
-		Prompts: prompts,
-	}, nil
+		Prompts: visiblePrompts(userId, prompts),
+	}, false, nil
 }
 
-func (a *Application) GetMultipleProfiles(ctx context.Context, ids []uuid.UUID) (profiles []domain.Profile, err error) {
+func (a *Application) GetMultipleProfiles(ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID) (profiles []domain.Profile, err error) {
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		profiles, err = a.getMultipleProfiles(ctx, ids)
+		profiles, err = a.getMultipleProfiles(ctx, viewerId, ids)
 		if err != nil {
 			return fmt.Errorf("failed to get profiles: %w", err)
 		}
 		return nil
-	})
+	}, postgres.ReadOnly(), postgres.OnPrimary())
 
 	return profiles, err
 }
 
-func (a *Application) getMultipleProfiles(ctx context.Context, ids []uuid.UUID) ([]domain.Profile, error) {
-	profiles, err := a.repository.GetMultipleProfilesByIDs(ctx, ids)
+func (a *Application) getMultipleProfiles(ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID) ([]domain.Profile, error) {
+	profiles, err := a.repository.GetMultipleProfilesByIDs(ctx, viewerId, ids)
 	if err != nil {
 		return nil, fmt.Errorf("get profiles by id: %w", err)
 	}
 
+	err = a.attachMainPictures(ctx, viewerId, profiles)
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// attachMainPictures fills the main picture links of the profiles that the
+// viewer can see.
+func (a *Application) attachMainPictures(ctx context.Context, viewerId uuid.UUID, profiles []domain.Profile) error {
 	profilesWithPics := lo.Filter(profiles, func(p domain.Profile, _ int) bool {
 		return p.MainPicPromptID != nil
 	})
@@ -267,12 +486,12 @@ func (a *Application) getMultipleProfiles(ctx context.Context, ids []uuid.UUID)
 		return *p.MainPicPromptID
 	})
 	if len(promptIds) == 0 {
-		return profiles, nil
+		return nil
 	}
 
 	prompts, err := a.repository.GetPromptsByIDs(ctx, promptIds)
 	if err != nil {
-		return nil, fmt.Errorf("get prompts for profile pics: %w", err)
+		return fmt.Errorf("get prompts for profile pics: %w", err)
 	}
 	promptIdsMap := lo.KeyBy(prompts, func(p domain.Prompt) uuid.UUID {
 		return p.ID
@@ -282,12 +501,11 @@ func (a *Application) getMultipleProfiles(ctx context.Context, ids []uuid.UUID)
 		if p.MainPicPromptID == nil {
 			continue
 		}
-		if prompt, ok := promptIdsMap[*p.MainPicPromptID]; ok {
-			profiles[i].MainPicLink = prompt.Content
+		if prompt, ok := promptIdsMap[*p.MainPicPromptID]; ok && prompt.VisibleTo(viewerId) {
+			profiles[i].MainPicLink, profiles[i].MainPicRenditions = prompt.Content, prompt.Renditions
 		}
 	}
-
-	return profiles, nil
+	return nil
 }
 
 func (a *Application) CreateProfile(ctx context.Context, profile *domain.Profile) (res *domain.Profile, err error) {
@@ -303,6 +521,9 @@ func (a *Application) CreateProfile(ctx context.Context, profile *domain.Profile
 		}
 		return nil
 	})
+	if err == nil {
+		metrics.ProfilesCreated.Inc()
+	}
 	return res, err
 }
 
@@ -311,17 +532,25 @@ func (a *Application) createProfile(ctx context.Context, profile *domain.Profile
 	if err == nil {
 		return nil, domain.ErrIDAlreadyExists
 	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
 	err = a.repository.CreateProfile(ctx, profile)
 	if err != nil {
 		return nil, err
 	}
+	profile.Visibility = domain.Visible
+	err = a.refreshCompleteness(ctx, profile.UserId)
+	if err != nil {
+		return nil, err
+	}
 
 	return profile, nil
 }
 
-func (a *Application) GetProfile(ctx context.Context, userId uuid.UUID) (profile *domain.Profile, err error) {
-	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		profile, err = a.getProfile(ctx, userId)
+func (a *Application) GetProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (profile *domain.Profile, err error) {
+	err = a.runInReadTx(ctx, viewerId, userId, func(ctx context.Context) error {
+		profile, err = a.getProfile(ctx, viewerId, userId)
 		if err != nil {
 			return fmt.Errorf("failed to get profile: %w", err)
 		}
@@ -330,8 +559,14 @@ func (a *Application) GetProfile(ctx context.Context, userId uuid.UUID) (profile
 	return profile, err
 }
 
-func (a *Application) getProfile(ctx context.Context, userId uuid.UUID) (*domain.Profile, error) {
-	p, err := a.repository.GetProfileByID(ctx, userId)
+// getProfile returns the profile as seen by the viewer: profiles hidden
+// from the viewer are reported as not found.
+func (a *Application) getProfile(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (p *domain.Profile, err error) {
+	if viewerId == userId {
+		p, err = a.repository.GetProfileByID(ctx, userId)
+	} else {
+		p, err = a.repository.GetProfileForViewer(ctx, viewerId, userId)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("get profile: %w", err)
 	}
@@ -340,7 +575,9 @@ func (a *Application) getProfile(ctx context.Context, userId uuid.UUID) (*domain
 		if err != nil {
 			return nil, fmt.Errorf("get prompt for profile pic: %w", err)
 		}
-		p.MainPicLink = prompt.Content
+		if prompt.VisibleTo(viewerId) {
+			p.MainPicLink, p.MainPicRenditions = prompt.Content, prompt.Renditions
+		}
 	}
 
 	return p, nil
@@ -358,6 +595,9 @@ func (a *Application) UpdateProfile(ctx context.Context, profile domain.Profile)
 		}
 		return nil
 	})
+	if err == nil {
+		metrics.ProfilesUpdated.Inc()
+	}
 	return res, err
 }
 
@@ -376,91 +616,159 @@ func (a *Application) updateProfile(ctx context.Context, profile domain.Profile)
 	if err != nil {
 		return nil, err
 	}
+	err = a.refreshCompleteness(ctx, p.UserId)
+	if err != nil {
+		return nil, err
+	}
 	if p.MainPicPromptID != nil {
 		prompt, err := a.repository.GetPromptByID(ctx, *p.MainPicPromptID)
 		if err != nil {
 			return nil, fmt.Errorf("get prompt for profile pic: %w", err)
 		}
-		p.MainPicLink = prompt.Content
+		p.MainPicLink, p.MainPicRenditions = prompt.Content, prompt.Renditions
 	}
 
 	return p, nil
 }
 
-func (a *Application) GetPrompts(ctx context.Context, userId uuid.UUID) ([]domain.Prompt, error) {
+func (a *Application) GetPrompts(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) (prompts []domain.Prompt, err error) {
+	err = a.runInReadTx(ctx, viewerId, userId, func(ctx context.Context) error {
+		prompts, err = a.getPrompts(ctx, viewerId, userId)
+		if err != nil {
+			return fmt.Errorf("failed to get prompts: %w", err)
+		}
+		return nil
+	})
+	return prompts, err
+}
+
+// getPrompts returns the prompts of the user as seen by the viewer: prompts
+// of profiles hidden from the viewer are reported as not found.
+func (a *Application) getPrompts(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID) ([]domain.Prompt, error) {
+	if viewerId != userId {
+		_, err := a.repository.GetProfileForViewer(ctx, viewerId, userId)
+		if err != nil {
+			return nil, fmt.Errorf("get profile: %w", err)
+		}
+	}
 	prompts, err := a.repository.GetPromptsByUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get prompts: %w", err)
+	}
+	err = a.attachGalleryImages(ctx, viewerId, prompts)
 	if err != nil {
 		return nil, err
 	}
+	return visiblePrompts(viewerId, prompts), nil
+}
 
-	return prompts, nil
+// runInReadTx runs f in a read-only transaction that may be served by a
+// replica. Users reading their own data are served by the primary, so that
+// they see their latest changes even when replicas lag behind. For the same
+// reason, recommendations and profile batches, which depend on the
+// requester's own preferences and blocks, are read from the primary.
+func (a *Application) runInReadTx(ctx context.Context, viewerId uuid.UUID, userId uuid.UUID, f func(ctx context.Context) error) error {
+	if viewerId == userId {
+		return a.txManager.RunInTx(ctx, f, postgres.ReadOnly(), postgres.OnPrimary())
+	}
+	return a.txManager.RunInTx(ctx, f, postgres.ReadOnly())
 }
 
 func (a *Application) AddPrompts(ctx context.Context, prompts []domain.Prompt) (res []domain.Prompt, err error) {
-	for _, prompt := range prompts {
+	for i, prompt := range prompts {
 		err = a.validate.Struct(prompt)
 		if err != nil {
 			return nil, fmt.Errorf("invalid prompt: %w", err)
 		}
+		prompts[i].ModerationStatus, err = a.checkPrompt(ctx, prompt, nil)
+		if err != nil {
+			return nil, fmt.Errorf("moderate prompt: %w", err)
+		}
 	}
 
+	// Serializable transactions keep concurrent calls from both passing
+	// the uniqueness and limit checks; conflicting calls are retried.
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
 		res, err = a.addPrompts(ctx, prompts)
 		if err != nil {
 			return fmt.Errorf("failed to add prompts: %w", err)
 		}
 		return nil
-	})
+	}, postgres.WithIsolation(postgres.Serializable))
+	if err == nil {
+		countAddedPrompts(res...)
+		a.moderateInBackground(ctx, res, nil)
+	}
 	return res, err
 }
 
+// countAddedPrompts counts committed prompts. It is called after the
+// transaction, which may run several times.
+func countAddedPrompts(prompts ...domain.Prompt) {
+	for _, prompt := range prompts {
+		metrics.PromptsAdded.WithLabelValues(string(prompt.Type)).Inc()
+	}
+}
+
 func (a *Application) addPrompts(ctx context.Context, prompts []domain.Prompt) ([]domain.Prompt, error) {
-	_, err := a.repository.GetProfileByID(ctx, prompts[0].UserId)
+	err := a.checkProfileExists(ctx, prompts[0].UserId)
 	if err != nil {
-		return nil, domain.ErrAddPromptsOnEmptyProfile
+		return nil, err
 	}
+	// The transaction may be retried, so the requested prompts are kept intact.
+	prompts = slices.Clone(prompts)
 	for i := range prompts {
 		prompts[i].ID = domain.NewUID()
-		err := a.addPrompt(ctx, prompts[i])
-		if err != nil {
-			return nil, err
+		if prompts[i].Type == domain.Gallery {
+			// Gallery images are added with AddGalleryImage.
+			prompts[i].Content = ""
 		}
 	}
 
-	return prompts, nil
+	return a.insertPrompts(ctx, prompts[0].UserId, prompts)
 }
 
-func (a *Application) addPrompt(ctx context.Context, prompt domain.Prompt) error {
+// checkProfileExists returns ErrAddPromptsOnEmptyProfile if the user has
+// no profile. Other errors are returned as they are, so that failed
+// transactions are retried.
+func (a *Application) checkProfileExists(ctx context.Context, userId uuid.UUID) error {
+	_, err := a.repository.GetProfileByID(ctx, userId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrAddPromptsOnEmptyProfile
+	}
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	return nil
+}
+
+func (a *Application) addPrompt(ctx context.Context, prompt *domain.Prompt) error {
 	_, err := a.repository.GetPromptByID(ctx, prompt.ID)
 	if err == nil {
 		return domain.ErrIDAlreadyExists
 	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("get prompt: %w", err)
+	}
 
-	_, err = a.repository.GetPromptByUserQuestionAndType(ctx, prompt)
+	err = a.applyCatalogQuestion(ctx, prompt, nil)
+	if err != nil {
+		return err
+	}
+	_, err = a.repository.GetPromptByUserQuestionAndType(ctx, *prompt)
 	if err == nil {
 		return domain.ErrNotUnique
 	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("get prompt by question: %w", err)
+	}
 
-	err = a.repository.CreatePrompt(ctx, prompt)
+	err = a.repository.CreatePrompt(ctx, *prompt)
 	if err != nil {
 		return fmt.Errorf("create prompt: %w", err)
 	}
 
-	var profile *domain.Profile
-	if prompt.Type == domain.Image {
-		profile, err = a.repository.GetProfileByID(ctx, prompt.UserId)
-		if err != nil {
-			return fmt.Errorf("get profile: %w", err)
-		}
-		if profile.MainPicPromptID == nil {
-			profile.MainPicPromptID = &prompt.ID
-			_, err = a.repository.UpdateProfile(ctx, *profile)
-			if err != nil {
-				return fmt.Errorf("update profile: %w", err)
-			}
-		}
-	}
-	return nil
+	return a.promoteMainPicture(ctx, *prompt)
 }
 
 func (a *Application) UpdatePrompt(ctx context.Context, prompt domain.Prompt) (res *domain.Prompt, err error) {
@@ -468,16 +776,39 @@ func (a *Application) UpdatePrompt(ctx context.Context, prompt domain.Prompt) (r
 	if err != nil {
 		return nil, fmt.Errorf("invalid prompt: %w", err)
 	}
+	prompt.ModerationStatus, err = a.checkPrompt(ctx, prompt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("moderate prompt: %w", err)
+	}
 	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
-		res, err = a.updatePrompt(ctx, prompt)
+		res, err = a.updateTextPrompt(ctx, prompt)
 		if err != nil {
 			return fmt.Errorf("failed to update prompt: %w", err)
 		}
 		return nil
-	})
+	}, postgres.WithIsolation(postgres.Serializable))
+	if err == nil {
+		a.moderateInBackground(ctx, []domain.Prompt{*res}, nil)
+	}
 	return res, err
 }
 
+// updateTextPrompt updates a prompt without a file. The content of photo,
+// audio and video prompts is a file, which is replaced with UpdateFilePrompt.
+func (a *Application) updateTextPrompt(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error) {
+	p, err := a.repository.GetPromptByID(ctx, prompt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt: %w", err)
+	}
+	if p.UserId.String() != prompt.UserId.String() {
+		return nil, domain.ErrForbidden
+	}
+	if p.Type == domain.Image || p.Type.IsMedia() {
+		return nil, domain.ErrFileRequired
+	}
+	return a.updatePrompt(ctx, prompt)
+}
+
 func (a *Application) updatePrompt(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error) {
 	p, err := a.repository.GetPromptByID(ctx, prompt.ID)
 	if err != nil {
@@ -487,16 +818,56 @@ func (a *Application) updatePrompt(ctx context.Context, prompt domain.Prompt) (*
 	if p.UserId.String() != prompt.UserId.String() {
 		return nil, domain.ErrForbidden
 	}
+	// Changing the type would bypass the limits of the new type.
+	if prompt.Type != p.Type {
+		return nil, domain.ErrPromptTypeChanged
+	}
+	position := prompt.Position
+	prompt.Position = p.Position
+	if p.Type == domain.Gallery {
+		prompt.Content = ""
+	}
+	// Updates without a new file keep the media metadata of the stored one.
+	if prompt.DurationMs == 0 {
+		prompt.DurationMs, prompt.ThumbnailLink = p.DurationMs, p.ThumbnailLink
+	}
+	if prompt.Renditions == nil && prompt.Content == p.Content {
+		prompt.Renditions = p.Renditions
+	}
+
+	// Updates without a question keep the stored one.
+	if prompt.QuestionId == nil {
+		prompt.QuestionId = p.QuestionId
+	}
 
+	err = a.applyCatalogQuestion(ctx, &prompt, p)
+	if err != nil {
+		return nil, err
+	}
 	p, err = a.repository.GetPromptByUserQuestionAndType(ctx, prompt)
 	if err == nil && p.ID.String() != prompt.ID.String() {
 		return nil, domain.ErrNotUnique
 	}
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get prompt by question: %w", err)
+	}
 
 	p, err = a.repository.UpdatePromptContent(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("update prompt: %w", err)
 	}
+	if position != p.Position {
+		layout, err := a.movePrompts(ctx, p.UserId, []domain.Prompt{{ID: p.ID, Position: position}})
+		if err != nil {
+			return nil, err
+		}
+		moved, _ := lo.Find(layout, func(l domain.Prompt) bool { return l.ID == p.ID })
+		p.Position = moved.Position
+	}
+	err = a.refreshCompleteness(ctx, p.UserId)
+	if err != nil {
+		return nil, err
+	}
 
 	return p, nil
 }
@@ -508,63 +879,169 @@ func (a *Application) UpdatePromptsPositions(ctx context.Context, prompts []doma
 			return fmt.Errorf("failed to update prompts positions: %w", err)
 		}
 		return nil
-	})
+	}, postgres.WithIsolation(postgres.Serializable))
 
 	return ps, err
 }
 
 func (a *Application) updatePromptsPositions(ctx context.Context, prompts []domain.Prompt) ([]domain.Prompt, error) {
-	ids := lo.Map(prompts, func(p domain.Prompt, _ int) uuid.UUID {
-		return p.ID
+	if len(prompts) == 0 {
+		return nil, domain.ErrInvalidPromptLayout
+	}
+	layout, err := a.movePrompts(ctx, prompts[0].UserId, prompts)
+	if err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+func New(ctx context.Context, cfg config.Config) App {
+	cluster, err := postgres.Connect(ctx, postgres.Config{
+		Host:                  cfg.Postgres.Host,
+		Port:                  cfg.Postgres.Port,
+		User:                  cfg.Postgres.User,
+		Password:              cfg.Postgres.Password,
+		DBName:                cfg.Postgres.Database,
+		SSLMode:               cfg.Postgres.SSLMode,
+		ConnectionTimeout:     cfg.Postgres.ConnectionTimeout,
+		DialTimeout:           cfg.Postgres.DialTimeout,
+		RetryInitialBackoff:   cfg.Postgres.RetryBackoff,
+		RetryMaxBackoff:       cfg.Postgres.RetryMaxBackoff,
+		MaxConns:              cfg.Postgres.MaxConns,
+		MinConns:              cfg.Postgres.MinConns,
+		MaxConnLifetime:       cfg.Postgres.MaxConnLifetime,
+		MaxConnLifetimeJitter: cfg.Postgres.MaxConnLifetimeJitter,
+		MaxConnIdleTime:       cfg.Postgres.MaxConnIdleTime,
+		HealthCheckPeriod:     cfg.Postgres.HealthCheckPeriod,
+		StatementTimeout:      cfg.Postgres.StatementTimeout,
+		ApplicationName:       cfg.Postgres.ApplicationName,
+		Replicas:              cfg.Postgres.Replicas,
 	})
-	dbPrompts, err := a.repository.GetPromptsByIDs(ctx, ids)
 	if err != nil {
-		return nil, fmt.Errorf("get prompts by ids: %w", err)
+		slog.Error("failed to connect to db", "error", err)
+		os.Exit(1)
 	}
-	if len(dbPrompts) < len(prompts) {
-		return nil, fmt.Errorf("prompts ids %w", domain.ErrNotFound)
+	if err = postgres.RegisterPoolMetrics("primary", cluster.Primary); err != nil {
+		slog.Error("failed to register db pool metrics", "error", err)
+		os.Exit(1)
 	}
-	promptMap := lo.KeyBy(prompts, func(p domain.Prompt) uuid.UUID {
-		return p.ID
-	})
-	lo.ForEach(dbPrompts, func(p domain.Prompt, i int) {
-		dbPrompts[i].Position = promptMap[p.ID].Position
+	for i, replica := range cluster.Replicas {
+		if err = postgres.RegisterPoolMetrics(fmt.Sprintf("replica-%d", i), replica); err != nil {
+			slog.Error("failed to register db pool metrics", "error", err)
+			os.Exit(1)
+		}
+	}
+	pool := postgres.NewPool(cluster.Primary, cluster.ReplicaConnections()...)
+	pool.SetRetryPolicy(postgres.RetryPolicy{
+		MaxAttempts:    cfg.Postgres.TxMaxAttempts,
+		InitialBackoff: cfg.Postgres.TxRetryBackoff,
+		MaxBackoff:     cfg.Postgres.TxRetryMaxBackoff,
 	})
-
-	err = a.repository.UpdatePromptsPositions(ctx, dbPrompts)
+	go pool.MonitorReplicas(ctx, cfg.Postgres.ReplicaCheckInterval, cfg.Postgres.ReplicaCheckTimeout)
+	pgRepo, err := postgres.NewRepo(pool, cfg.Prompts.SearchLanguage)
 	if err != nil {
-		return nil, fmt.Errorf("update prompts positions: %w", err)
+		slog.Error("failed to create repository", "error", err)
+		os.Exit(1)
+	}
+	var repo Repository = pgRepo
+	var txManager TransactionManager = pool
+	replicas := len(cluster.Replicas) > 0
+	switch cfg.Cache.Backend {
+	case "none":
+	case "lru":
+		c := cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL)
+		repo, txManager = newCachedRepository(repo, c, replicas), newCachedTxManager(txManager, c)
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Cache.RedisAddress,
+			Password: cfg.Cache.RedisPassword,
+			DB:       cfg.Cache.RedisDB,
+		})
+		if err = client.Ping(ctx).Err(); err != nil {
+			slog.Error("failed to connect to redis", "error", err)
+			os.Exit(1)
+		}
+		c := cache.NewRedis(client, cfg.Cache.RedisPrefix, cfg.Cache.TTL)
+		repo, txManager = newCachedRepository(repo, c, replicas), newCachedTxManager(txManager, c)
+	default:
+		slog.Error("unknown cache backend", "backend", cfg.Cache.Backend)
+		os.Exit(1)
 	}
 
-	sort.Slice(dbPrompts, func(i, j int) bool {
-		return dbPrompts[i].Position < dbPrompts[j].Position
+	moderator, err := moderation.New(moderation.Config{
+		BlockedWords:     cfg.Moderation.BlockedWords,
+		BlockedPatterns:  cfg.Moderation.BlockedPatterns,
+		ImageBlocklist:   cfg.Moderation.ImageBlocklist,
+		ImageMaxDistance: cfg.Moderation.ImageMaxDistance,
+		MaxImagePixels:   cfg.Prompts.MaxImagePixels,
 	})
+	if err != nil {
+		slog.Error("failed to configure moderation", "error", err)
+		os.Exit(1)
+	}
+	moderationMode := moderation.Mode(cfg.Moderation.Mode)
+	if moderationMode != moderation.Sync && moderationMode != moderation.Async {
+		slog.Error("unknown moderation mode", "mode", cfg.Moderation.Mode)
+		os.Exit(1)
+	}
 
-	return dbPrompts, nil
-}
+	if cfg.Moderation.DuplicateMaxDistance > postgres.MaxDuplicateDistance {
+		slog.Error("duplicate max distance is too large",
+			"distance", cfg.Moderation.DuplicateMaxDistance, "max", postgres.MaxDuplicateDistance)
+		os.Exit(1)
+	}
 
-func New(ctx context.Context, cfg config.Config) App {
-	conn, err := postgres.Connect(ctx, postgres.Config{
-		Host:              cfg.Postgres.Host,
-		Port:              cfg.Postgres.Port,
-		User:              cfg.Postgres.User,
-		Password:          cfg.Postgres.Password,
-		DBName:            cfg.Postgres.Database,
-		SSLMode:           cfg.Postgres.SSLMode,
-		ConnectionTimeout: cfg.Postgres.ConnectionTimeout,
-	})
+	renditionSizes, err := imaging.ParseSizes(cfg.Prompts.Renditions)
 	if err != nil {
-		log.Fatalf("failed to connect to db: %v", err)
+		slog.Error("invalid renditions", "error", err)
+		os.Exit(1)
 	}
-	pool := postgres.NewPool(conn)
-	repo := postgres.NewRepo(pool)
 
 	mediaClient, err := media.NewServiceClient(media.Config{
-		Address:   cfg.Media.Address,
-		EnableTLS: cfg.Media.EnableTLS,
+		Address:            cfg.Media.Address,
+		EnableTLS:          cfg.Media.EnableTLS,
+		CAFile:             cfg.Media.CAFile,
+		CertFile:           cfg.Media.CertFile,
+		KeyFile:            cfg.Media.KeyFile,
+		ServerName:         cfg.Media.ServerName,
+		Timeout:            cfg.Media.Timeout,
+		KeepaliveTime:      cfg.Media.KeepaliveTime,
+		KeepaliveTimeout:   cfg.Media.KeepaliveTimeout,
+		BreakerFailures:    cfg.Media.BreakerFailures,
+		BreakerOpenTimeout: cfg.Media.BreakerOpenTimeout,
 	})
 	if err != nil {
-		log.Fatalf("could not connect to media service: %s", err.Error())
+		slog.Error("could not connect to media service", "error", err)
+		os.Exit(1)
+	}
+	return &Application{
+		repository:  repo,
+		mediaClient: mediaClient,
+		txManager:   txManager,
+		validate:    validator.New(),
+		promptLimits: domain.PromptLimits{
+			MaxText:    cfg.Prompts.MaxText,
+			MaxImage:   cfg.Prompts.MaxImage,
+			MaxAudio:   cfg.Prompts.MaxAudio,
+			MaxVideo:   cfg.Prompts.MaxVideo,
+			MaxGallery: cfg.Prompts.MaxGallery,
+		},
+		maxGalleryImages: cfg.Prompts.MaxGalleryImages,
+		maxFullProfiles:  cfg.Prompts.MaxFullProfilesBatch,
+		mediaLimits: domain.MediaLimits{
+			AudioCodecs:      cfg.Prompts.AudioCodecs,
+			VideoCodecs:      cfg.Prompts.VideoCodecs,
+			MaxAudioDuration: cfg.Prompts.MaxAudioDuration,
+			MaxVideoDuration: cfg.Prompts.MaxVideoDuration,
+		},
+		minPhotos:         cfg.Prompts.MinPhotosToBeDiscoverable,
+		minCompleteness:   cfg.Prompts.MinCompletenessToBeDiscoverable,
+		moderator:         moderator,
+		moderationMode:    moderationMode,
+		moderationTimeout: cfg.Moderation.Timeout,
+		duplicateDistance: cfg.Moderation.DuplicateMaxDistance,
+		renditionSizes:    renditionSizes,
+		maxImagePixels:    cfg.Prompts.MaxImagePixels,
 	}
-	return &Application{repository: repo, mediaClient: mediaClient, txManager: pool, validate: validator.New()}
 }
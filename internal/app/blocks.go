@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+func (a *Application) BlockUser(ctx context.Context, userId uuid.UUID, blockedId uuid.UUID) (block *domain.Block, err error) {
+	if userId == blockedId {
+		return nil, domain.ErrCannotBlockSelf
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		block, err = a.blockUser(ctx, userId, blockedId)
+		if err != nil {
+			return fmt.Errorf("failed to block user: %w", err)
+		}
+		return nil
+	})
+	return block, err
+}
+
+func (a *Application) blockUser(ctx context.Context, userId uuid.UUID, blockedId uuid.UUID) (*domain.Block, error) {
+	_, err := a.repository.GetProfileByID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	_, err = a.repository.GetProfileByID(ctx, blockedId)
+	if err != nil {
+		return nil, fmt.Errorf("get blocked profile: %w", err)
+	}
+
+	block, err := a.repository.CreateBlock(ctx, userId, blockedId)
+	if err != nil {
+		return nil, fmt.Errorf("create block: %w", err)
+	}
+	return block, nil
+}
+
+func (a *Application) UnblockUser(ctx context.Context, userId uuid.UUID, blockedId uuid.UUID) (block *domain.Block, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		block, err = a.repository.DeleteBlock(ctx, userId, blockedId)
+		if err != nil {
+			return fmt.Errorf("failed to unblock user: %w", err)
+		}
+		return nil
+	})
+	return block, err
+}
+
+func (a *Application) ListBlockedUsers(ctx context.Context, userId uuid.UUID) (blocks []domain.Block, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		blocks, err = a.repository.GetBlocksByBlocker(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("failed to list blocked users: %w", err)
+		}
+		return nil
+	})
+	return blocks, err
+}
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+)
+
+// Cache stores encoded values. Misses are reported with false, not errors.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, keys ...string) error
+	Purge(ctx context.Context) error
+}
+
+// cachedRepository serves the profile and prompt reads of full profiles and
+// recommendations from a cache and invalidates them on every write.
+//
+// Values are only read from the cache outside transactions and in read-only
+// ones, so that writes never rely on cached rows. They are only written to it
+// from reads of the primary, as rows read from lagging replicas would hide
+// the latest writes from readers of the primary until they expire.
+type cachedRepository struct {
+	Repository
+	cache Cache
+	// replicas is whether reads may be served by replicas.
+	replicas bool
+}
+
+func newCachedRepository(repository Repository, cache Cache, replicas bool) *cachedRepository {
+	return &cachedRepository{Repository: repository, cache: cache, replicas: replicas}
+}
+
+// cachedTxManager tracks the keys invalidated by a transaction and
+// invalidates them again once it ends, dropping the values that concurrent
+// read-only transactions cached from the rows it was changing.
+type cachedTxManager struct {
+	TransactionManager
+	cache Cache
+}
+
+func newCachedTxManager(txManager TransactionManager, cache Cache) *cachedTxManager {
+	return &cachedTxManager{TransactionManager: txManager, cache: cache}
+}
+
+type txStateKey struct{}
+
+type txState struct {
+	readOnly  bool
+	onPrimary bool
+
+	mu    sync.Mutex
+	keys  []string
+	purge bool
+}
+
+func txStateFrom(ctx context.Context) *txState {
+	state, _ := ctx.Value(txStateKey{}).(*txState)
+	return state
+}
+
+func (m *cachedTxManager) RunInTx(ctx context.Context, f func(ctx context.Context) error, opts ...postgres.TxOption) error {
+	if txStateFrom(ctx) != nil {
+		return m.TransactionManager.RunInTx(ctx, f, opts...)
+	}
+
+	options := postgres.NewTxOptions(opts...)
+	state := &txState{readOnly: options.ReadOnly, onPrimary: options.OnPrimary || !options.ReadOnly}
+	err := m.TransactionManager.RunInTx(context.WithValue(ctx, txStateKey{}, state), f, opts...)
+
+	// The invalidation must happen even when the request was canceled.
+	ctx = context.WithoutCancel(ctx)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.purge {
+		purgeCache(ctx, m.cache)
+	} else {
+		deleteFromCache(ctx, m.cache, state.keys...)
+	}
+	return err
+}
+
+func profileCacheKey(id uuid.UUID) string {
+	return "profile:" + id.String()
+}
+
+func promptCacheKey(id uuid.UUID) string {
+	return "prompt:" + id.String()
+}
+
+func userPromptsCacheKey(userId uuid.UUID) string {
+	return "prompts:" + userId.String()
+}
+
+func (r *cachedRepository) GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profile, error) {
+	return cached(ctx, r.cache, r.readsPrimary(ctx), "profile", profileCacheKey(id), func() (*domain.Profile, error) {
+		return r.Repository.GetProfileByID(ctx, id)
+	})
+}
+
+func (r *cachedRepository) GetPromptByID(ctx context.Context, id uuid.UUID) (*domain.Prompt, error) {
+	return cached(ctx, r.cache, r.readsPrimary(ctx), "prompt", promptCacheKey(id), func() (*domain.Prompt, error) {
+		return r.Repository.GetPromptByID(ctx, id)
+	})
+}
+
+func (r *cachedRepository) GetPromptsByUser(ctx context.Context, userId uuid.UUID) ([]domain.Prompt, error) {
+	return cached(ctx, r.cache, r.readsPrimary(ctx), "user_prompts", userPromptsCacheKey(userId), func() ([]domain.Prompt, error) {
+		return r.Repository.GetPromptsByUser(ctx, userId)
+	})
+}
+
+func (r *cachedRepository) CreateProfile(ctx context.Context, p *domain.Profile) error {
+	r.invalidate(ctx, profileCacheKey(p.UserId))
+	return r.Repository.CreateProfile(ctx, p)
+}
+
+func (r *cachedRepository) UpdateProfile(ctx context.Context, profile domain.Profile) (*domain.Profile, error) {
+	r.invalidate(ctx, profileCacheKey(profile.UserId))
+	return r.Repository.UpdateProfile(ctx, profile)
+}
+
+func (r *cachedRepository) UpdateProfileVisibility(
+	ctx context.Context, userId uuid.UUID, visibility domain.Visibility,
+) (*domain.Profile, error) {
+	r.invalidate(ctx, profileCacheKey(userId))
+	return r.Repository.UpdateProfileVisibility(ctx, userId, visibility)
+}
+
+func (r *cachedRepository) UpdateProfileSuspended(ctx context.Context, userId uuid.UUID, suspended bool) (*domain.Profile, error) {
+	r.invalidate(ctx, profileCacheKey(userId))
+	return r.Repository.UpdateProfileSuspended(ctx, userId, suspended)
+}
+
+func (r *cachedRepository) UpdateProfileCompleteness(ctx context.Context, userId uuid.UUID) error {
+	r.invalidate(ctx, profileCacheKey(userId))
+	return r.Repository.UpdateProfileCompleteness(ctx, userId)
+}
+
+func (r *cachedRepository) CreatePrompt(ctx context.Context, prompt domain.Prompt) error {
+	if err := r.invalidatePrompts(ctx, prompt); err != nil {
+		return err
+	}
+	return r.Repository.CreatePrompt(ctx, prompt)
+}
+
+func (r *cachedRepository) UpdatePromptContent(ctx context.Context, prompt domain.Prompt) (*domain.Prompt, error) {
+	if err := r.invalidatePrompts(ctx, prompt); err != nil {
+		return nil, err
+	}
+	return r.Repository.UpdatePromptContent(ctx, prompt)
+}
+
+func (r *cachedRepository) UpdatePromptsPositions(ctx context.Context, prompts []domain.Prompt) error {
+	if err := r.invalidatePrompts(ctx, prompts...); err != nil {
+		return err
+	}
+	return r.Repository.UpdatePromptsPositions(ctx, prompts)
+}
+
+func (r *cachedRepository) UpdatePromptModerationStatus(ctx context.Context, prompt domain.Prompt) error {
+	if err := r.invalidatePrompts(ctx, prompt); err != nil {
+		return err
+	}
+	return r.Repository.UpdatePromptModerationStatus(ctx, prompt)
+}
+
+func (r *cachedRepository) DeletePrompt(ctx context.Context, id uuid.UUID) error {
+	if err := r.invalidatePrompts(ctx, domain.Prompt{ID: id}); err != nil {
+		return err
+	}
+	return r.Repository.DeletePrompt(ctx, id)
+}
+
+// UpdatePromptsQuestionText changes prompts of any user, so the whole cache
+// is dropped. Questions are rarely renamed.
+func (r *cachedRepository) UpdatePromptsQuestionText(ctx context.Context, questionId uuid.UUID, text string) error {
+	purgeCache(ctx, r.cache)
+	if state := txStateFrom(ctx); state != nil {
+		state.mu.Lock()
+		state.purge = true
+		state.mu.Unlock()
+	}
+	return r.Repository.UpdatePromptsQuestionText(ctx, questionId, text)
+}
+
+// invalidatePrompts invalidates the prompts and the prompt lists of their
+// owners. The owners of prompts without one are looked up.
+func (r *cachedRepository) invalidatePrompts(ctx context.Context, prompts ...domain.Prompt) error {
+	keys := make([]string, 0, 2*len(prompts))
+	for _, p := range prompts {
+		keys = append(keys, promptCacheKey(p.ID))
+		if p.UserId == uuid.Nil {
+			stored, err := r.Repository.GetPromptByID(ctx, p.ID)
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			p.UserId = stored.UserId
+		}
+		keys = append(keys, userPromptsCacheKey(p.UserId))
+	}
+	r.invalidate(ctx, keys...)
+	return nil
+}
+
+// readsPrimary reports whether reads in the context are served by the primary.
+func (r *cachedRepository) readsPrimary(ctx context.Context) bool {
+	if !r.replicas {
+		return true
+	}
+	state := txStateFrom(ctx)
+	return state != nil && state.onPrimary
+}
+
+func (r *cachedRepository) invalidate(ctx context.Context, keys ...string) {
+	deleteFromCache(ctx, r.cache, keys...)
+	if state := txStateFrom(ctx); state != nil {
+		state.mu.Lock()
+		state.keys = append(state.keys, keys...)
+		state.mu.Unlock()
+	}
+}
+
+// cached returns the value of the key, loading it on a miss and caching it
+// when store is set. Cache failures fall back to load.
+func cached[T any](ctx context.Context, cache Cache, store bool, entity string, key string, load func() (T, error)) (T, error) {
+	if state := txStateFrom(ctx); state != nil && !state.readOnly {
+		return load()
+	}
+
+	data, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		metrics.CacheErrors.WithLabelValues("get").Inc()
+		slog.WarnContext(ctx, "failed to read from cache", "key", key, "error", err)
+	}
+	if ok {
+		var value T
+		if err = json.Unmarshal(data, &value); err == nil {
+			metrics.CacheRequests.WithLabelValues(entity, "hit").Inc()
+			return value, nil
+		}
+		slog.WarnContext(ctx, "failed to decode cached value", "key", key, "error", err)
+	}
+	metrics.CacheRequests.WithLabelValues(entity, "miss").Inc()
+
+	value, err := load()
+	if err != nil || !store {
+		return value, err
+	}
+	data, err = json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to encode value to cache", "key", key, "error", err)
+		return value, nil
+	}
+	if err = cache.Set(ctx, key, data); err != nil {
+		metrics.CacheErrors.WithLabelValues("set").Inc()
+		slog.WarnContext(ctx, "failed to write to cache", "key", key, "error", err)
+	}
+	return value, nil
+}
+
+func deleteFromCache(ctx context.Context, cache Cache, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := cache.Delete(ctx, keys...); err != nil {
+		metrics.CacheErrors.WithLabelValues("delete").Inc()
+		slog.ErrorContext(ctx, "failed to invalidate cache", "keys", keys, "error", err)
+	}
+}
+
+func purgeCache(ctx context.Context, cache Cache) {
+	if err := cache.Purge(ctx); err != nil {
+		metrics.CacheErrors.WithLabelValues("purge").Inc()
+		slog.ErrorContext(ctx, "failed to purge cache", "error", err)
+	}
+}
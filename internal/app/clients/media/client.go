@@ -1,26 +1,181 @@
 package media
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+	"github.com/soulmate-dating/profiles/internal/requestid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type Config struct {
 	Address   string
 	EnableTLS bool
+	// CAFile verifies the server certificate instead of the system roots.
+	CAFile string
+	// CertFile and KeyFile enable mutual TLS.
+	CertFile   string
+	KeyFile    string
+	ServerName string
+
+	// Timeout bounds every call. Calls are not retried: UploadFile is not
+	// idempotent, as the media service may have stored the file before the
+	// call failed.
+	Timeout time.Duration
+
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+
+	// The circuit breaker opens after BreakerFailures consecutive failures,
+	// at least 1, and lets a trial call through after BreakerOpenTimeout.
+	BreakerFailures    uint32
+	BreakerOpenTimeout time.Duration
 }
 
 func NewServiceClient(cfg Config) (c MediaServiceClient, err error) {
-	var cc *grpc.ClientConn
+	if cfg.BreakerFailures < 1 {
+		return nil, fmt.Errorf("breaker failures must be at least 1, got %d", cfg.BreakerFailures)
+	}
+	creds := insecure.NewCredentials()
 	if cfg.EnableTLS {
-		cc, err = grpc.Dial(cfg.Address, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	} else {
-		cc, err = grpc.Dial(cfg.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
 	}
+	cc, err := grpc.NewClient(cfg.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(
+			requestIDInterceptor,
+			metricsInterceptor,
+			breakerInterceptor(cfg),
+			timeoutInterceptor(cfg.Timeout),
+		),
+	)
 	if err != nil {
 		return nil, err
 	}
 	return NewMediaServiceClient(cc), nil
 }
+
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read media CA: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load media client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// requestIDInterceptor forwards the request ID of the incoming call to the media service.
+func requestIDInterceptor(
+	ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestid.Header, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func metricsInterceptor(
+	ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	metrics.MediaRequestDuration.
+		WithLabelValues(path.Base(method), status.Code(err).String()).
+		Observe(time.Since(start).Seconds())
+	return err
+}
+
+// breakerInterceptor fails fast with Unavailable while the media service
+// keeps failing. Errors caused by the request itself do not count as failures.
+func breakerInterceptor(cfg Config) grpc.UnaryClientInterceptor {
+	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:    "media",
+		Timeout: cfg.BreakerOpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= cfg.BreakerFailures
+		},
+		OnStateChange: func(_ string, from gobreaker.State, to gobreaker.State) {
+			metrics.MediaBreakerState.Set(float64(to))
+			metrics.MediaBreakerTransitions.WithLabelValues(from.String(), to.String()).Inc()
+		},
+		IsSuccessful: func(err error) bool {
+			return err == nil || !isServiceFailure(status.Code(err))
+		},
+	})
+
+	return func(
+		ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		_, err := cb.Execute(func() (interface{}, error) {
+			return nil, invoker(ctx, method, req, reply, cc, opts...)
+		})
+		if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+			return status.Errorf(codes.Unavailable, "media service is unavailable: %s", err)
+		}
+		return err
+	}
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func isServiceFailure(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
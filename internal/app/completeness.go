@@ -0,0 +1,19 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// refreshCompleteness stores the completeness score recommendations are
+// filtered by. It must be called after every change of the profile or of its
+// prompts and gallery images.
+func (a *Application) refreshCompleteness(ctx context.Context, userId uuid.UUID) error {
+	err := a.repository.UpdateProfileCompleteness(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("update completeness: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+// GetMultipleFullProfiles returns the full profiles in the order of the
+// requested IDs, loading the profiles and their prompts in two queries.
+func (a *Application) GetMultipleFullProfiles(
+	ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID,
+) (profiles *domain.FullProfiles, err error) {
+	ids = lo.Uniq(ids)
+	if len(ids) > a.maxFullProfiles {
+		return nil, fmt.Errorf("%w: %d profiles, at most %d", domain.ErrBatchTooLarge, len(ids), a.maxFullProfiles)
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		profiles, err = a.getMultipleFullProfiles(ctx, viewerId, ids)
+		if err != nil {
+			return fmt.Errorf("failed to get full profiles: %w", err)
+		}
+		return nil
+	}, postgres.ReadOnly(), postgres.OnPrimary())
+	return profiles, err
+}
+
+func (a *Application) getMultipleFullProfiles(
+	ctx context.Context, viewerId uuid.UUID, ids []uuid.UUID,
+) (*domain.FullProfiles, error) {
+	res := &domain.FullProfiles{Profiles: make([]domain.FullProfile, 0, len(ids))}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	profiles, err := a.repository.GetMultipleProfilesByIDs(ctx, viewerId, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get profiles by id: %w", err)
+	}
+	profilesById := lo.KeyBy(profiles, func(p domain.Profile) uuid.UUID {
+		return p.UserId
+	})
+
+	// The images of gallery prompts are loaded with the prompts.
+	prompts, err := a.repository.GetPromptsByUsers(ctx, lo.Keys(profilesById))
+	if err != nil {
+		return nil, fmt.Errorf("get prompts by users: %w", err)
+	}
+	showGalleryImages(viewerId, prompts)
+	promptsById := lo.KeyBy(prompts, func(p domain.Prompt) uuid.UUID {
+		return p.ID
+	})
+	promptsByUser := lo.GroupBy(prompts, func(p domain.Prompt) uuid.UUID {
+		return p.UserId
+	})
+
+	for _, id := range ids {
+		p, ok := profilesById[id]
+		if !ok {
+			res.MissingIds = append(res.MissingIds, id)
+			continue
+		}
+		if p.MainPicPromptID != nil {
+			if prompt, ok := promptsById[*p.MainPicPromptID]; ok && prompt.VisibleTo(viewerId) {
+				p.MainPicLink, p.MainPicRenditions = prompt.Content, prompt.Renditions
+			}
+		}
+		res.Profiles = append(res.Profiles, domain.FullProfile{
+			Profile: p,
+			Prompts: visiblePrompts(viewerId, promptsByUser[id]),
+		})
+	}
+	return res, nil
+}
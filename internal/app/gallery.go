@@ -0,0 +1,263 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/imaging"
+)
+
+func (a *Application) AddGalleryImage(ctx context.Context, upload domain.GalleryUpload) (prompt *domain.Prompt, err error) {
+	err = a.validate.Struct(upload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gallery image: %w", err)
+	}
+	mimeType, err := imaging.MimeType(upload.Content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gallery image: %w: %w", domain.ErrUnsupportedMedia, err)
+	}
+	status, err := a.checkContent(ctx, []string{upload.Caption}, upload.Content)
+	if err != nil {
+		return nil, fmt.Errorf("moderate gallery image: %w", err)
+	}
+	// The gallery is checked before uploading, and again in the transaction.
+	// A replica may not have the images added just before.
+	_, err = a.checkGalleryCapacity(postgres.WithPrimary(ctx), upload.UserId, upload.PromptId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add gallery image: %w", err)
+	}
+	// Uploads are not repeated when the transaction is retried.
+	file, err := a.uploadFile(ctx, upload.UserId, domain.Gallery, mimeType, upload.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add gallery image: %w", err)
+	}
+
+	var image *domain.GalleryImage
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		prompt, image, err = a.addGalleryImage(ctx, upload, file, status)
+		if err != nil {
+			return fmt.Errorf("failed to add gallery image: %w", err)
+		}
+		return nil
+	}, postgres.WithIsolation(postgres.Serializable))
+	if err == nil {
+		a.moderateLater(ctx, []string{upload.Caption}, upload.Content, func(ctx context.Context, status domain.ModerationStatus) error {
+			image.ModerationStatus = status
+			err := a.repository.UpdateGalleryImageModerationStatus(ctx, *image)
+			if err != nil {
+				return err
+			}
+			return a.refreshCompleteness(ctx, upload.UserId)
+		})
+	}
+	return prompt, err
+}
+
+func (a *Application) addGalleryImage(
+	ctx context.Context, upload domain.GalleryUpload, file *domain.MediaUpload, status domain.ModerationStatus,
+) (*domain.Prompt, *domain.GalleryImage, error) {
+	prompt, err := a.checkGalleryCapacity(ctx, upload.UserId, upload.PromptId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	position := min(int(upload.Position), len(prompt.Images))
+	images := slices.Insert(prompt.Images, position, domain.GalleryImage{
+		ID:               domain.NewUID(),
+		PromptId:         prompt.ID,
+		Link:             file.Link,
+		Caption:          upload.Caption,
+		ModerationStatus: status,
+		Renditions:       file.Renditions,
+	})
+	err = a.saveImagePositions(ctx, images, position)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := a.repository.CreateGalleryImage(ctx, images[position])
+	if err != nil {
+		return nil, nil, fmt.Errorf("create gallery image: %w", err)
+	}
+	images[position] = *image
+	prompt.Images = images
+	err = a.refreshCompleteness(ctx, upload.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prompt, image, nil
+}
+
+func (a *Application) RemoveGalleryImage(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageId uuid.UUID) (prompt *domain.Prompt, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		prompt, err = a.removeGalleryImage(ctx, userId, promptId, imageId)
+		if err != nil {
+			return fmt.Errorf("failed to remove gallery image: %w", err)
+		}
+		return nil
+	})
+	return prompt, err
+}
+
+func (a *Application) removeGalleryImage(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageId uuid.UUID) (*domain.Prompt, error) {
+	prompt, err := a.getGalleryPrompt(ctx, userId, promptId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = a.repository.DeleteGalleryImage(ctx, promptId, imageId)
+	if err != nil {
+		return nil, fmt.Errorf("delete gallery image: %w", err)
+	}
+
+	images := lo.Reject(prompt.Images, func(i domain.GalleryImage, _ int) bool {
+		return i.ID == imageId
+	})
+	err = a.saveImagePositions(ctx, images, -1)
+	if err != nil {
+		return nil, err
+	}
+	err = a.refreshCompleteness(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Images = images
+	return prompt, nil
+}
+
+func (a *Application) ReorderGalleryImages(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageIds []uuid.UUID) (prompt *domain.Prompt, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		prompt, err = a.reorderGalleryImages(ctx, userId, promptId, imageIds)
+		if err != nil {
+			return fmt.Errorf("failed to reorder gallery images: %w", err)
+		}
+		return nil
+	})
+	return prompt, err
+}
+
+// reorderGalleryImages expects the ids of all the images of the gallery in
+// their new order.
+func (a *Application) reorderGalleryImages(ctx context.Context, userId uuid.UUID, promptId uuid.UUID, imageIds []uuid.UUID) (*domain.Prompt, error) {
+	prompt, err := a.getGalleryPrompt(ctx, userId, promptId)
+	if err != nil {
+		return nil, err
+	}
+	if len(lo.Uniq(imageIds)) != len(imageIds) || len(imageIds) != len(prompt.Images) {
+		return nil, domain.ErrInvalidPromptLayout
+	}
+
+	byId := lo.KeyBy(prompt.Images, func(i domain.GalleryImage) uuid.UUID { return i.ID })
+	images := make([]domain.GalleryImage, len(imageIds))
+	for i, id := range imageIds {
+		image, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("gallery image %s: %w", id, domain.ErrNotFound)
+		}
+		images[i] = image
+	}
+	err = a.saveImagePositions(ctx, images, -1)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Images = images
+	return prompt, nil
+}
+
+// checkGalleryCapacity returns the user's gallery prompt if it has room for
+// another image.
+func (a *Application) checkGalleryCapacity(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Prompt, error) {
+	prompt, err := a.getGalleryPrompt(ctx, userId, promptId)
+	if err != nil {
+		return nil, err
+	}
+	if len(prompt.Images) >= a.maxGalleryImages {
+		return nil, domain.ErrTooManyGalleryImages
+	}
+	return prompt, nil
+}
+
+// getGalleryPrompt returns the user's gallery prompt with all its images.
+func (a *Application) getGalleryPrompt(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Prompt, error) {
+	prompt, err := a.repository.GetPromptByID(ctx, promptId)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt: %w", err)
+	}
+	if prompt.UserId != userId {
+		return nil, domain.ErrForbidden
+	}
+	if prompt.Type != domain.Gallery {
+		return nil, domain.ErrNotGalleryPrompt
+	}
+	prompt.Images, err = a.repository.GetGalleryImagesByPrompts(ctx, []uuid.UUID{promptId})
+	if err != nil {
+		return nil, fmt.Errorf("get gallery images: %w", err)
+	}
+	return prompt, nil
+}
+
+// saveImagePositions renumbers the images to consecutive positions and
+// stores the changed ones, except the image at index skip which is not
+// stored yet.
+func (a *Application) saveImagePositions(ctx context.Context, images []domain.GalleryImage, skip int) error {
+	var changed []domain.GalleryImage
+	for i := range images {
+		if images[i].Position != int32(i) && i != skip {
+			changed = append(changed, images[i])
+			changed[len(changed)-1].Position = int32(i)
+		}
+		images[i].Position = int32(i)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	err := a.repository.UpdateGalleryImagesPositions(ctx, changed)
+	if err != nil {
+		return fmt.Errorf("update gallery images positions: %w", err)
+	}
+	return nil
+}
+
+// attachGalleryImages loads the images of the gallery prompts that the
+// viewer may see. The first image is used as the content of the prompt for
+// clients that do not support galleries.
+func (a *Application) attachGalleryImages(ctx context.Context, viewerId uuid.UUID, prompts []domain.Prompt) error {
+	galleries := lo.FilterMap(prompts, func(p domain.Prompt, _ int) (uuid.UUID, bool) {
+		return p.ID, p.Type == domain.Gallery
+	})
+	if len(galleries) == 0 {
+		return nil
+	}
+	images, err := a.repository.GetGalleryImagesByPrompts(ctx, galleries)
+	if err != nil {
+		return fmt.Errorf("get gallery images: %w", err)
+	}
+	byPrompt := lo.GroupBy(images, func(i domain.GalleryImage) uuid.UUID { return i.PromptId })
+	for i, p := range prompts {
+		if p.Type == domain.Gallery {
+			prompts[i].Images = byPrompt[p.ID]
+		}
+	}
+	showGalleryImages(viewerId, prompts)
+	return nil
+}
+
+// showGalleryImages keeps the images of gallery prompts that the viewer may
+// see.
+func showGalleryImages(viewerId uuid.UUID, prompts []domain.Prompt) {
+	for i, p := range prompts {
+		if p.Type != domain.Gallery {
+			continue
+		}
+		prompts[i].Images = lo.Filter(p.Images, func(image domain.GalleryImage, _ int) bool {
+			return p.UserId == viewerId || image.ModerationStatus == domain.ModerationApproved
+		})
+		if len(prompts[i].Images) > 0 {
+			prompts[i].Content = prompts[i].Images[0].Link
+			prompts[i].Renditions = prompts[i].Images[0].Renditions
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+// insertPrompts creates the prompts at their requested positions, shifting
+// the other prompts of the user and compacting the positions.
+func (a *Application) insertPrompts(ctx context.Context, userId uuid.UUID, added []domain.Prompt) ([]domain.Prompt, error) {
+	existing, err := a.repository.GetPromptsByUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get prompts: %w", err)
+	}
+	err = a.promptLimits.Check(existing, added)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := domain.LayoutPrompts(existing, added)
+	if err != nil {
+		return nil, err
+	}
+	err = a.savePositions(ctx, existing, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := lo.SliceToMap(layout, func(p domain.Prompt) (uuid.UUID, int32) {
+		return p.ID, p.Position
+	})
+	for i := range added {
+		added[i].Position = positions[added[i].ID]
+		err := a.addPrompt(ctx, &added[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = a.refreshCompleteness(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// movePrompts moves the user's prompts to their requested positions and
+// compacts the positions of the other prompts. It returns the resulting layout.
+func (a *Application) movePrompts(ctx context.Context, userId uuid.UUID, moved []domain.Prompt) ([]domain.Prompt, error) {
+	existing, err := a.repository.GetPromptsByUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get prompts: %w", err)
+	}
+	if len(lo.UniqBy(moved, func(p domain.Prompt) uuid.UUID { return p.ID })) != len(moved) {
+		return nil, domain.ErrInvalidPromptLayout
+	}
+
+	requested := lo.SliceToMap(moved, func(p domain.Prompt) (uuid.UUID, int32) {
+		return p.ID, p.Position
+	})
+	var others, movedPrompts []domain.Prompt
+	for _, p := range existing {
+		if position, ok := requested[p.ID]; ok {
+			p.Position = position
+			movedPrompts = append(movedPrompts, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+	if len(movedPrompts) != len(moved) {
+		return nil, fmt.Errorf("prompts ids %w", domain.ErrNotFound)
+	}
+
+	layout, err := domain.LayoutPrompts(others, movedPrompts)
+	if err != nil {
+		return nil, err
+	}
+	err = a.savePositions(ctx, existing, layout)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// compactPrompts renumbers the user's prompts to consecutive positions.
+func (a *Application) compactPrompts(ctx context.Context, userId uuid.UUID) error {
+	_, err := a.movePrompts(ctx, userId, nil)
+	return err
+}
+
+func (a *Application) savePositions(ctx context.Context, original []domain.Prompt, layout []domain.Prompt) error {
+	changed := domain.ChangedPositions(original, layout)
+	if len(changed) == 0 {
+		return nil
+	}
+	err := a.repository.UpdatePromptsPositions(ctx, changed)
+	if err != nil {
+		return fmt.Errorf("update prompts positions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+const (
+	defaultProfilesLimit = 50
+	maxProfilesLimit     = 200
+)
+
+// ListProfiles lists profiles matching the filter for admin tools,
+// including suspended and hidden ones.
+func (a *Application) ListProfiles(
+	ctx context.Context, filter domain.ProfileFilter, cursor *domain.ProfileCursor, limit int,
+) (page *domain.ProfilePage, err error) {
+	err = a.validate.Struct(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile filter: %w", err)
+	}
+	if limit <= 0 {
+		limit = defaultProfilesLimit
+	}
+	limit = min(limit, maxProfilesLimit)
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		page, err = a.listProfiles(ctx, filter, cursor, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list profiles: %w", err)
+		}
+		return nil
+	}, postgres.ReadOnly())
+	return page, err
+}
+
+func (a *Application) listProfiles(
+	ctx context.Context, filter domain.ProfileFilter, cursor *domain.ProfileCursor, limit int,
+) (*domain.ProfilePage, error) {
+	// One more profile than requested tells whether there is a next page.
+	profiles, err := a.repository.ListProfiles(ctx, filter, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.ProfilePage{Profiles: profiles}
+	if len(profiles) > limit {
+		page.Profiles = profiles[:limit]
+		page.Next = domain.NewProfileCursor(page.Profiles[limit-1])
+	}
+	// Admin tools show the main pictures other users see.
+	err = a.attachMainPictures(ctx, uuid.Nil, page.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
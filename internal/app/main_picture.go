@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+func (a *Application) SetMainPicture(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (profile *domain.Profile, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		profile, err = a.setMainPicture(ctx, userId, promptId)
+		if err != nil {
+			return fmt.Errorf("failed to set main picture: %w", err)
+		}
+		return nil
+	})
+	return profile, err
+}
+
+func (a *Application) setMainPicture(ctx context.Context, userId uuid.UUID, promptId uuid.UUID) (*domain.Profile, error) {
+	prompt, err := a.repository.GetPromptByID(ctx, promptId)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt: %w", err)
+	}
+	if prompt.UserId.String() != userId.String() {
+		return nil, domain.ErrForbidden
+	}
+	if prompt.Type != domain.Image {
+		return nil, domain.ErrNotImagePrompt
+	}
+	if prompt.ModerationStatus != domain.ModerationApproved {
+		return nil, domain.ErrImageNotApproved
+	}
+
+	profile, err := a.repository.GetProfileByID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	profile.MainPicPromptID = &prompt.ID
+	profile, err = a.repository.UpdateProfile(ctx, *profile)
+	if err != nil {
+		return nil, fmt.Errorf("update profile: %w", err)
+	}
+	err = a.refreshCompleteness(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	profile.MainPicLink, profile.MainPicRenditions = prompt.Content, prompt.Renditions
+	return profile, nil
+}
+
+// promoteMainPicture makes the image the user's main picture if the user has
+// none and the image is approved. Images added before moderation are
+// promoted once they are approved.
+func (a *Application) promoteMainPicture(ctx context.Context, prompt domain.Prompt) error {
+	if prompt.Type != domain.Image || prompt.ModerationStatus != domain.ModerationApproved {
+		return nil
+	}
+	profile, err := a.repository.GetProfileByID(ctx, prompt.UserId)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	if profile.MainPicPromptID != nil {
+		return nil
+	}
+	profile.MainPicPromptID = &prompt.ID
+	_, err = a.repository.UpdateProfile(ctx, *profile)
+	if err != nil {
+		return fmt.Errorf("update profile: %w", err)
+	}
+	return nil
+}
+
+// replaceMainPicture must be called before the prompt is deleted. If the prompt
+// is the user's main picture, the next approved image by position becomes the
+// main picture, or it is cleared when no other approved image is left.
+func (a *Application) replaceMainPicture(ctx context.Context, userId uuid.UUID, removedId uuid.UUID) error {
+	profile, err := a.repository.GetProfileByID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	if profile.MainPicPromptID == nil || *profile.MainPicPromptID != removedId {
+		return nil
+	}
+
+	prompts, err := a.repository.GetPromptsByUser(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("get prompts: %w", err)
+	}
+	profile.MainPicPromptID = nil
+	next, ok := lo.Find(prompts, func(p domain.Prompt) bool {
+		return p.Type == domain.Image && p.ID != removedId && p.ModerationStatus == domain.ModerationApproved
+	})
+	if ok {
+		profile.MainPicPromptID = &next.ID
+	}
+	_, err = a.repository.UpdateProfile(ctx, *profile)
+	if err != nil {
+		return fmt.Errorf("update profile: %w", err)
+	}
+	return nil
+}
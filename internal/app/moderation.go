@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/moderation"
+)
+
+// checkPrompt returns the moderation status the prompt is stored with.
+// In sync mode rejected content fails the request, in async mode the
+// prompt is stored as pending and checked by moderateInBackground.
+func (a *Application) checkPrompt(ctx context.Context, prompt domain.Prompt, image []byte) (domain.ModerationStatus, error) {
+	return a.checkContent(ctx, promptTexts(prompt), image)
+}
+
+// moderateInBackground checks the stored prompts once the request is done
+// and updates their moderation status unless their content changed since.
+// Approved images become the main picture of users without one, and
+// rejected main pictures are replaced.
+func (a *Application) moderateInBackground(ctx context.Context, prompts []domain.Prompt, image []byte) {
+	for _, prompt := range prompts {
+		prompt := prompt
+		a.moderateLater(ctx, promptTexts(prompt), image, func(ctx context.Context, status domain.ModerationStatus) error {
+			prompt.ModerationStatus = status
+			err := a.repository.UpdatePromptModerationStatus(ctx, prompt)
+			if err != nil {
+				return err
+			}
+			// The status is not updated if the prompt changed or was deleted since.
+			stored, err := a.repository.GetPromptByID(ctx, prompt.ID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("get prompt: %w", err)
+			}
+			if stored.ModerationStatus == domain.ModerationRejected {
+				err = a.replaceMainPicture(ctx, stored.UserId, stored.ID)
+			} else {
+				err = a.promoteMainPicture(ctx, *stored)
+			}
+			if err != nil {
+				return err
+			}
+			return a.refreshCompleteness(ctx, prompt.UserId)
+		})
+	}
+}
+
+// moderateLater checks the content in the background in async mode and
+// stores the resulting status with update.
+func (a *Application) moderateLater(
+	ctx context.Context, texts []string, image []byte,
+	update func(ctx context.Context, status domain.ModerationStatus) error,
+) {
+	if a.moderationMode != moderation.Async {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	a.moderating.Add(1)
+	go func() {
+		defer a.moderating.Done()
+		ctx, cancel := context.WithTimeout(ctx, a.moderationTimeout)
+		defer cancel()
+		status, err := a.moderateContent(ctx, texts, image)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to moderate content", "error", err)
+			return
+		}
+		err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+			return update(ctx, status)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update moderation status", "error", err)
+		}
+	}()
+}
+
+func (a *Application) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.moderating.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for background moderation: %w", ctx.Err())
+	}
+}
+
+// checkContent is checkPrompt for content that is not a prompt.
+func (a *Application) checkContent(ctx context.Context, texts []string, image []byte) (domain.ModerationStatus, error) {
+	if a.moderationMode == moderation.Async {
+		return domain.ModerationPending, nil
+	}
+	status, err := a.moderateContent(ctx, texts, image)
+	if err != nil {
+		return "", err
+	}
+	if status == domain.ModerationRejected {
+		return "", domain.ErrContentRejected
+	}
+	return status, nil
+}
+
+func promptTexts(prompt domain.Prompt) []string {
+	var texts []string
+	if prompt.QuestionId == nil {
+		texts = append(texts, prompt.Question)
+	}
+	if prompt.Type == domain.Text {
+		texts = append(texts, prompt.Content)
+	}
+	return texts
+}
+
+func (a *Application) moderateContent(ctx context.Context, texts []string, image []byte) (domain.ModerationStatus, error) {
+	status, err := a.moderator.CheckText(ctx, strings.Join(texts, "\n"))
+	if err != nil {
+		return "", fmt.Errorf("moderate text: %w", err)
+	}
+	if status == domain.ModerationRejected || image == nil {
+		return status, nil
+	}
+	imageStatus, err := a.moderator.CheckImage(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("moderate image: %w", err)
+	}
+	if imageStatus != domain.ModerationApproved {
+		return imageStatus, nil
+	}
+	return status, nil
+}
+
+// visiblePrompts drops the prompts whose content the viewer may not see.
+func visiblePrompts(viewerId uuid.UUID, prompts []domain.Prompt) []domain.Prompt {
+	return lo.Filter(prompts, func(p domain.Prompt, _ int) bool {
+		return p.VisibleTo(viewerId)
+	})
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+func (a *Application) ListPromptQuestions(ctx context.Context, category string, includeInactive bool) (questions []domain.PromptQuestion, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		questions, err = a.repository.GetPromptQuestions(ctx, category, includeInactive)
+		if err != nil {
+			return fmt.Errorf("failed to list prompt questions: %w", err)
+		}
+		return nil
+	}, postgres.ReadOnly())
+	return questions, err
+}
+
+func (a *Application) CreatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (res *domain.PromptQuestion, err error) {
+	err = a.validate.Struct(question)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt question: %w", err)
+	}
+	question.ID = domain.NewUID()
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		res, err = a.repository.CreatePromptQuestion(ctx, question)
+		if err != nil {
+			return fmt.Errorf("failed to create prompt question: %w", err)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (a *Application) UpdatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (res *domain.PromptQuestion, err error) {
+	err = a.validate.Struct(question)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt question: %w", err)
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		res, err = a.updatePromptQuestion(ctx, question)
+		if err != nil {
+			return fmt.Errorf("failed to update prompt question: %w", err)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (a *Application) updatePromptQuestion(ctx context.Context, question domain.PromptQuestion) (*domain.PromptQuestion, error) {
+	q, err := a.repository.UpdatePromptQuestion(ctx, question)
+	if err != nil {
+		return nil, fmt.Errorf("update prompt question: %w", err)
+	}
+	// Users may have a free-text prompt with the new text already.
+	taken, err := a.repository.IsPromptQuestionTextTaken(ctx, q.ID, q.Text(domain.DefaultLocale))
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, domain.ErrQuestionTextTaken
+	}
+	err = a.repository.UpdatePromptsQuestionText(ctx, q.ID, q.Text(domain.DefaultLocale))
+	if err != nil {
+		return nil, fmt.Errorf("update prompts question text: %w", err)
+	}
+	return q, nil
+}
+
+func (a *Application) DeletePromptQuestion(ctx context.Context, id uuid.UUID) (question *domain.PromptQuestion, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		question, err = a.deletePromptQuestion(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete prompt question: %w", err)
+		}
+		return nil
+	})
+	return question, err
+}
+
+func (a *Application) deletePromptQuestion(ctx context.Context, id uuid.UUID) (*domain.PromptQuestion, error) {
+	q, err := a.repository.GetPromptQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get prompt question: %w", err)
+	}
+	used, err := a.repository.IsPromptQuestionUsed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, domain.ErrQuestionInUse
+	}
+	err = a.repository.DeletePromptQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// applyCatalogQuestion checks that the catalog question referenced by the
+// prompt accepts its content type and stores the question text on the prompt.
+// Inactive questions are only accepted for the stored prompt already using
+// them, which is nil for new prompts. Legacy free-text prompts are left
+// untouched.
+func (a *Application) applyCatalogQuestion(ctx context.Context, prompt *domain.Prompt, stored *domain.Prompt) error {
+	if prompt.QuestionId == nil {
+		return nil
+	}
+	q, err := a.repository.GetPromptQuestionByID(ctx, *prompt.QuestionId)
+	if err != nil {
+		return fmt.Errorf("get prompt question: %w", err)
+	}
+	if !q.Active && (stored == nil || stored.QuestionId == nil || *stored.QuestionId != q.ID) {
+		return domain.ErrQuestionInactive
+	}
+	if !q.Allows(prompt.Type) {
+		return domain.ErrContentTypeNotAllowed
+	}
+	prompt.Question = q.Text(domain.DefaultLocale)
+	return nil
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+const (
+	defaultReportsLimit = 50
+	maxReportsLimit     = 200
+)
+
+func (a *Application) ReportProfile(ctx context.Context, report domain.Report) (res *domain.Report, err error) {
+	err = a.validate.Struct(report)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report: %w", err)
+	}
+	if report.ReporterId == nil {
+		return nil, fmt.Errorf("reporter %w", domain.ErrNotFound)
+	}
+	if *report.ReporterId == report.ReportedId {
+		return nil, domain.ErrCannotReportSelf
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		res, err = a.reportProfile(ctx, report)
+		if err != nil {
+			return fmt.Errorf("failed to report profile: %w", err)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func (a *Application) reportProfile(ctx context.Context, report domain.Report) (*domain.Report, error) {
+	_, err := a.repository.GetProfileByID(ctx, *report.ReporterId)
+	if err != nil {
+		return nil, fmt.Errorf("get reporter profile: %w", err)
+	}
+	_, err = a.repository.GetProfileByID(ctx, report.ReportedId)
+	if err != nil {
+		return nil, fmt.Errorf("get reported profile: %w", err)
+	}
+	if report.PromptId != nil {
+		prompt, err := a.repository.GetPromptByID(ctx, *report.PromptId)
+		if err != nil {
+			return nil, fmt.Errorf("get reported prompt: %w", err)
+		}
+		if prompt.UserId != report.ReportedId {
+			return nil, fmt.Errorf("reported prompt %w", domain.ErrNotFound)
+		}
+	}
+
+	report.ID = domain.NewUID()
+	res, err := a.repository.CreateReport(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+	return res, nil
+}
+
+func (a *Application) ListReports(ctx context.Context, status domain.ReportStatus, limit, offset int) (reports []domain.Report, err error) {
+	if limit <= 0 {
+		limit = defaultReportsLimit
+	}
+	limit = min(limit, maxReportsLimit)
+	offset = max(offset, 0)
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		reports, err = a.repository.GetReports(ctx, status, limit, offset)
+		if err != nil {
+			return fmt.Errorf("failed to list reports: %w", err)
+		}
+		return nil
+	})
+	return reports, err
+}
+
+func (a *Application) ClaimReport(ctx context.Context, reportId uuid.UUID, moderatorId uuid.UUID) (report *domain.Report, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		report, err = a.claimReport(ctx, reportId, moderatorId)
+		if err != nil {
+			return fmt.Errorf("failed to claim report: %w", err)
+		}
+		return nil
+	})
+	return report, err
+}
+
+func (a *Application) claimReport(ctx context.Context, reportId uuid.UUID, moderatorId uuid.UUID) (*domain.Report, error) {
+	_, err := a.repository.GetReportByID(ctx, reportId)
+	if err != nil {
+		return nil, fmt.Errorf("get report: %w", err)
+	}
+	report, err := a.repository.ClaimReport(ctx, reportId, moderatorId)
+	if err != nil {
+		return nil, fmt.Errorf("claim report: %w", err)
+	}
+	return report, nil
+}
+
+func (a *Application) ResolveReport(ctx context.Context, resolution domain.Resolution) (report *domain.Report, err error) {
+	err = a.validate.Struct(resolution)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolution: %w", err)
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		report, err = a.resolveReport(ctx, resolution)
+		if err != nil {
+			return fmt.Errorf("failed to resolve report: %w", err)
+		}
+		return nil
+	})
+	return report, err
+}
+
+func (a *Application) resolveReport(ctx context.Context, resolution domain.Resolution) (*domain.Report, error) {
+	report, err := a.repository.GetReportByID(ctx, resolution.ReportId)
+	if err != nil {
+		return nil, fmt.Errorf("get report: %w", err)
+	}
+	if report.Status != domain.ReportClaimed || report.ModeratorId == nil || *report.ModeratorId != resolution.ModeratorId {
+		return nil, domain.ErrReportNotClaimed
+	}
+
+	switch resolution.Action {
+	case domain.ActionRemovePrompt:
+		err = a.removeReportedPrompt(ctx, report)
+		if err != nil {
+			return nil, err
+		}
+	case domain.ActionSuspendProfile:
+		_, err = a.repository.UpdateProfileSuspended(ctx, report.ReportedId, true)
+		if err != nil {
+			return nil, fmt.Errorf("suspend profile: %w", err)
+		}
+	}
+
+	report, err = a.repository.ResolveReport(ctx, resolution)
+	if err != nil {
+		return nil, fmt.Errorf("resolve report: %w", err)
+	}
+	return report, nil
+}
+
+func (a *Application) removeReportedPrompt(ctx context.Context, report *domain.Report) error {
+	if report.PromptId == nil {
+		return domain.ErrReportWithoutPrompt
+	}
+	_, err := a.repository.GetPromptByID(ctx, *report.PromptId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get reported prompt: %w", err)
+	}
+
+	err = a.replaceMainPicture(ctx, report.ReportedId, *report.PromptId)
+	if err != nil {
+		return err
+	}
+
+	err = a.repository.DeletePrompt(ctx, *report.PromptId)
+	if err != nil {
+		return fmt.Errorf("delete reported prompt: %w", err)
+	}
+	err = a.compactPrompts(ctx, report.ReportedId)
+	if err != nil {
+		return err
+	}
+	return a.refreshCompleteness(ctx, report.ReportedId)
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchPrompts finds the prompts matching the query among the profiles
+// the viewer may see.
+func (a *Application) SearchPrompts(
+	ctx context.Context, viewerId uuid.UUID, query string, limit, offset int,
+) (result *domain.PromptSearchResult, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrEmptySearchQuery
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit, offset = min(limit, maxSearchLimit), max(offset, 0)
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		result, err = a.searchPrompts(ctx, viewerId, query, limit, offset)
+		if err != nil {
+			return fmt.Errorf("failed to search prompts: %w", err)
+		}
+		return nil
+	}, postgres.ReadOnly())
+	return result, err
+}
+
+func (a *Application) searchPrompts(
+	ctx context.Context, viewerId uuid.UUID, query string, limit, offset int,
+) (*domain.PromptSearchResult, error) {
+	matches, err := a.repository.SearchPrompts(ctx, viewerId, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	prompts := lo.Map(matches, func(m domain.PromptMatch, _ int) domain.Prompt {
+		return m.Prompt
+	})
+	err = a.attachGalleryImages(ctx, viewerId, prompts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		matches[i].Prompt = prompts[i]
+	}
+
+	ownerIds := lo.Uniq(lo.Map(matches, func(m domain.PromptMatch, _ int) uuid.UUID {
+		return m.UserId
+	}))
+	profiles, err := a.getMultipleProfiles(ctx, viewerId, ownerIds)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PromptSearchResult{Matches: matches, Profiles: profiles}, nil
+}
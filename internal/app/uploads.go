@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/soulmate-dating/profiles/internal/adapters/postgres"
+	"github.com/soulmate-dating/profiles/internal/app/clients/media"
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/imaging"
+	"github.com/soulmate-dating/profiles/internal/medialinks"
+	"github.com/soulmate-dating/profiles/internal/metrics"
+	"github.com/soulmate-dating/profiles/internal/moderation"
+)
+
+const maxReportedDuplicates = 10
+
+// uploadFile uploads the file to the media service and returns the upload
+// holding its object key and, for photos, the keys of its renditions.
+// Files the user already uploaded are not uploaded again. As it uploads
+// files, it must be called outside transactions that may be retried.
+func (a *Application) uploadFile(
+	ctx context.Context, userId uuid.UUID, contentType domain.ContentType, mimeType string, data []byte,
+) (*domain.MediaUpload, error) {
+	sum := sha256.Sum256(data)
+	// A replica may not have the upload recorded by a retried request yet.
+	existing, err := a.repository.GetMediaUpload(postgres.WithPrimary(ctx), userId, sum[:])
+	if err == nil {
+		metrics.DeduplicatedUploads.WithLabelValues(string(contentType)).Inc()
+		return existing, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get media upload: %w", err)
+	}
+
+	key, err := a.sendFile(ctx, contentType, mimeType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := domain.MediaUpload{UserId: userId, Sha256: sum[:], Link: key}
+	if strings.HasPrefix(mimeType, "image/") {
+		if hash, err := moderation.PerceptualHash(data, a.maxImagePixels); err == nil {
+			upload.PerceptualHash = lo.ToPtr(int64(hash))
+		}
+	}
+	if contentType == domain.Image || contentType == domain.Gallery {
+		upload.Renditions, err = a.uploadRenditions(ctx, contentType, data, key)
+		if err != nil {
+			return nil, fmt.Errorf("upload renditions: %w", err)
+		}
+	}
+	// The upload is only recorded once checked, so that the check is
+	// repeated when it fails and the file is uploaded again.
+	var duplicate bool
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		err := a.repository.CreateMediaUpload(ctx, upload)
+		if err != nil {
+			return fmt.Errorf("create media upload: %w", err)
+		}
+		if strings.HasPrefix(mimeType, "image/") {
+			duplicate, err = a.flagDuplicatePhoto(ctx, upload)
+			if err != nil {
+				return fmt.Errorf("check duplicate photos: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		metrics.DuplicatePhotosFlagged.Inc()
+	}
+	return &upload, nil
+}
+
+// sendFile uploads the file to the media service and returns its object key.
+func (a *Application) sendFile(ctx context.Context, contentType domain.ContentType, mimeType string, data []byte) (string, error) {
+	metrics.FileUploadSize.WithLabelValues(string(contentType)).Observe(float64(len(data)))
+	response, err := a.mediaClient.UploadFile(ctx, &media.UploadFileRequest{
+		ContentType: mimeType,
+		Data:        data,
+	})
+	if err != nil {
+		return "", err
+	}
+	return medialinks.KeyFromLink(response.GetLink()), nil
+}
+
+// uploadRenditions uploads a scaled down copy of the photo for every
+// configured rendition. Renditions the photo already fits in reuse the
+// original, and photos that cannot be decoded get no renditions.
+func (a *Application) uploadRenditions(
+	ctx context.Context, contentType domain.ContentType, data []byte, key string,
+) (domain.Renditions, error) {
+	if len(a.renditionSizes) == 0 {
+		return nil, nil
+	}
+	img, err := imaging.Decode(data, a.maxImagePixels)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping renditions of undecodable image", "error", err)
+		return nil, nil
+	}
+	renditions := make(domain.Renditions, len(a.renditionSizes))
+	for _, size := range a.renditionSizes {
+		if img.Fits(size) {
+			renditions[size.Name] = key
+			continue
+		}
+		resized, err := img.Resize(size)
+		if err != nil {
+			return nil, fmt.Errorf("resize to %s: %w", size.Name, err)
+		}
+		renditions[size.Name], err = a.sendFile(ctx, contentType, "image/jpeg", resized)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return renditions, nil
+}
+
+// flagDuplicatePhoto reports the uploader for moderation when other users
+// uploaded the same or a nearly identical photo, which is typical for
+// accounts using someone else's pictures. It reports whether duplicates
+// were found.
+func (a *Application) flagDuplicatePhoto(ctx context.Context, upload domain.MediaUpload) (bool, error) {
+	if a.duplicateDistance < 0 {
+		return false, nil
+	}
+	duplicates, err := a.repository.FindSameUploads(ctx, upload, maxReportedDuplicates)
+	if err != nil {
+		return false, err
+	}
+	if len(duplicates) == 0 {
+		duplicates, err = a.repository.FindSimilarUploads(ctx, upload, a.duplicateDistance, maxReportedDuplicates)
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(duplicates) == 0 {
+		return false, nil
+	}
+	reported, err := a.repository.HasOpenReport(ctx, upload.UserId, domain.ReasonDuplicatePhoto)
+	if err != nil {
+		return false, err
+	}
+	if reported {
+		return true, nil
+	}
+	owners := lo.Uniq(lo.Map(duplicates, func(d domain.MediaUpload, _ int) string {
+		return d.UserId.String()
+	}))
+	_, err = a.repository.CreateReport(ctx, domain.Report{
+		ID:         domain.NewUID(),
+		ReportedId: upload.UserId,
+		Reason:     domain.ReasonDuplicatePhoto,
+		Comment:    fmt.Sprintf("photo %s matches photos uploaded by %s", upload.Link, strings.Join(owners, ", ")),
+	})
+	if err != nil {
+		return false, fmt.Errorf("create report: %w", err)
+	}
+	return true, nil
+}
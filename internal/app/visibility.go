@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+func (a *Application) SetVisibility(ctx context.Context, userId uuid.UUID, visibility domain.Visibility) (profile *domain.Profile, err error) {
+	err = a.validate.Var(visibility, "oneof=visible paused incognito")
+	if err != nil {
+		return nil, fmt.Errorf("invalid visibility: %w", err)
+	}
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		profile, err = a.setVisibility(ctx, userId, visibility)
+		if err != nil {
+			return fmt.Errorf("failed to set visibility: %w", err)
+		}
+		return nil
+	})
+	return profile, err
+}
+
+func (a *Application) setVisibility(ctx context.Context, userId uuid.UUID, visibility domain.Visibility) (*domain.Profile, error) {
+	_, err := a.repository.UpdateProfileVisibility(ctx, userId, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("update profile visibility: %w", err)
+	}
+	return a.getProfile(ctx, userId, userId)
+}
+
+func (a *Application) AllowViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (viewer *domain.AllowedViewer, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		viewer, err = a.allowViewer(ctx, userId, viewerId)
+		if err != nil {
+			return fmt.Errorf("failed to allow viewer: %w", err)
+		}
+		return nil
+	})
+	return viewer, err
+}
+
+func (a *Application) allowViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (*domain.AllowedViewer, error) {
+	_, err := a.repository.GetProfileByID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("get profile: %w", err)
+	}
+	_, err = a.repository.GetProfileByID(ctx, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("get viewer profile: %w", err)
+	}
+
+	viewer, err := a.repository.CreateAllowedViewer(ctx, userId, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("create allowed viewer: %w", err)
+	}
+	return viewer, nil
+}
+
+func (a *Application) DisallowViewer(ctx context.Context, userId uuid.UUID, viewerId uuid.UUID) (viewer *domain.AllowedViewer, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		viewer, err = a.repository.DeleteAllowedViewer(ctx, userId, viewerId)
+		if err != nil {
+			return fmt.Errorf("failed to disallow viewer: %w", err)
+		}
+		return nil
+	})
+	return viewer, err
+}
+
+func (a *Application) ListAllowedViewers(ctx context.Context, userId uuid.UUID) (viewers []domain.AllowedViewer, err error) {
+	err = a.txManager.RunInTx(ctx, func(ctx context.Context) error {
+		viewers, err = a.repository.GetAllowedViewersByUser(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("failed to list allowed viewers: %w", err)
+		}
+		return nil
+	})
+	return viewers, err
+}
@@ -15,6 +15,28 @@ type Postgres struct {
 	Database          string        `env:"POSTGRES_DB,required" example:"glimpse"`
 	SSLMode           string        `env:"POSTGRES_SSL_MODE" envDefault:"disable"`
 	ConnectionTimeout time.Duration `env:"POSTGRES_CONNECTION_TIMEOUT" envDefault:"60s"`
+	DialTimeout       time.Duration `env:"POSTGRES_DIAL_TIMEOUT" envDefault:"5s"`
+	RetryBackoff      time.Duration `env:"POSTGRES_RETRY_BACKOFF" envDefault:"500ms"`
+	RetryMaxBackoff   time.Duration `env:"POSTGRES_RETRY_MAX_BACKOFF" envDefault:"5s"`
+
+	// Zero pool sizes keep the pgx defaults.
+	MaxConns              int32         `env:"POSTGRES_MAX_CONNS" envDefault:"0"`
+	MinConns              int32         `env:"POSTGRES_MIN_CONNS" envDefault:"0"`
+	MaxConnLifetime       time.Duration `env:"POSTGRES_MAX_CONN_LIFETIME" envDefault:"1h"`
+	MaxConnLifetimeJitter time.Duration `env:"POSTGRES_MAX_CONN_LIFETIME_JITTER" envDefault:"0s"`
+	MaxConnIdleTime       time.Duration `env:"POSTGRES_MAX_CONN_IDLE_TIME" envDefault:"30m"`
+	HealthCheckPeriod     time.Duration `env:"POSTGRES_HEALTH_CHECK_PERIOD" envDefault:"1m"`
+	StatementTimeout      time.Duration `env:"POSTGRES_STATEMENT_TIMEOUT" envDefault:"0s"`
+	ApplicationName       string        `env:"POSTGRES_APPLICATION_NAME" envDefault:"profiles"`
+
+	// Transactions failing with a serialization failure or a deadlock are retried.
+	TxMaxAttempts     int           `env:"POSTGRES_TX_MAX_ATTEMPTS" envDefault:"3"`
+	TxRetryBackoff    time.Duration `env:"POSTGRES_TX_RETRY_BACKOFF" envDefault:"20ms"`
+	TxRetryMaxBackoff time.Duration `env:"POSTGRES_TX_RETRY_MAX_BACKOFF" envDefault:"500ms"`
+
+	Replicas             []string      `env:"POSTGRES_REPLICAS" envSeparator:"," example:"replica-1:5432,replica-2:5432"`
+	ReplicaCheckInterval time.Duration `env:"POSTGRES_REPLICA_CHECK_INTERVAL" envDefault:"5s"`
+	ReplicaCheckTimeout  time.Duration `env:"POSTGRES_REPLICA_CHECK_TIMEOUT" envDefault:"2s"`
 }
 
 type API struct {
@@ -22,22 +44,109 @@ type API struct {
 	Address        string `env:"API_ADDRESS,required" example:"localhost:8080"`
 	MaxReceiveSize int    `env:"API_MAX_RECEIVE_SIZE" envDefault:"20"`
 	MaxSendSize    int    `env:"API_MAX_SEND_SIZE" envDefault:"20"`
+	MaxUploadSize  int    `env:"API_MAX_UPLOAD_SIZE" envDefault:"50"`
 }
 
 type Media struct {
-	Address   string `env:"MEDIA_ADDRESS,required" example:"localhost:8081"`
-	EnableTLS bool   `env:"MEDIA_ENABLE_TLS" envDefault:"false"`
+	Address    string `env:"MEDIA_ADDRESS,required" example:"localhost:8081"`
+	EnableTLS  bool   `env:"MEDIA_ENABLE_TLS" envDefault:"false"`
+	CAFile     string `env:"MEDIA_CA_FILE"`
+	CertFile   string `env:"MEDIA_CERT_FILE"`
+	KeyFile    string `env:"MEDIA_KEY_FILE"`
+	ServerName string `env:"MEDIA_SERVER_NAME"`
+
+	Timeout          time.Duration `env:"MEDIA_TIMEOUT" envDefault:"10s"`
+	KeepaliveTime    time.Duration `env:"MEDIA_KEEPALIVE_TIME" envDefault:"30s"`
+	KeepaliveTimeout time.Duration `env:"MEDIA_KEEPALIVE_TIMEOUT" envDefault:"10s"`
+
+	BreakerFailures    uint32        `env:"MEDIA_BREAKER_FAILURES" envDefault:"5"`
+	BreakerOpenTimeout time.Duration `env:"MEDIA_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+}
+
+type MediaLinks struct {
+	BaseURL    string        `env:"MEDIA_LINKS_BASE_URL,required,notEmpty" example:"https://media.example.com"`
+	CDNBaseURL string        `env:"MEDIA_LINKS_CDN_BASE_URL" example:"https://cdn.example.com"`
+	Secret     string        `env:"MEDIA_LINKS_SECRET,required,notEmpty"`
+	TTL        time.Duration `env:"MEDIA_LINKS_TTL" envDefault:"1h"`
 }
 
 type Metrics struct {
 	Address string `env:"METRICS_ADDRESS,required" example:"localhost:8084"`
 }
 
+type Prompts struct {
+	MaxText                   int `env:"PROMPTS_MAX_TEXT" envDefault:"6"`
+	MaxImage                  int `env:"PROMPTS_MAX_IMAGE" envDefault:"6"`
+	MinPhotosToBeDiscoverable int `env:"PROMPTS_MIN_PHOTOS_TO_BE_DISCOVERABLE" envDefault:"0"`
+	MaxAudio                  int `env:"PROMPTS_MAX_AUDIO" envDefault:"1"`
+	MaxVideo                  int `env:"PROMPTS_MAX_VIDEO" envDefault:"1"`
+	MaxGallery                int `env:"PROMPTS_MAX_GALLERY" envDefault:"1"`
+	MaxGalleryImages          int `env:"PROMPTS_MAX_GALLERY_IMAGES" envDefault:"6"`
+	// MinCompletenessToBeDiscoverable is the completeness score, from 0 to 100,
+	// profiles need to be recommended.
+	MinCompletenessToBeDiscoverable int `env:"PROMPTS_MIN_COMPLETENESS_TO_BE_DISCOVERABLE" envDefault:"0"`
+	// MaxFullProfilesBatch is the number of full profiles that can be
+	// requested at once.
+	MaxFullProfilesBatch int `env:"PROMPTS_MAX_FULL_PROFILES_BATCH" envDefault:"50"`
+
+	AudioCodecs      []string      `env:"PROMPTS_AUDIO_CODECS" envSeparator:"," envDefault:"mp4a,opus,vorbis"`
+	VideoCodecs      []string      `env:"PROMPTS_VIDEO_CODECS" envSeparator:"," envDefault:"avc1,hvc1,hev1,mp4a"`
+	MaxAudioDuration time.Duration `env:"PROMPTS_MAX_AUDIO_DURATION" envDefault:"60s"`
+	MaxVideoDuration time.Duration `env:"PROMPTS_MAX_VIDEO_DURATION" envDefault:"30s"`
+	// Renditions are the scaled down copies generated for every photo, as name:max_side.
+	Renditions []string `env:"PROMPTS_RENDITIONS" envSeparator:"," envDefault:"thumbnail:160,card:640,full:1600"`
+	// MaxImagePixels is the number of pixels of the largest photo that is
+	// decoded for moderation and renditions.
+	MaxImagePixels int `env:"PROMPTS_MAX_IMAGE_PIXELS" envDefault:"50000000"`
+	// SearchLanguage is the text search configuration prompts are searched
+	// with, such as english or simple. The migrations build the search index
+	// for english; other languages need their index created beforehand.
+	SearchLanguage string `env:"PROMPTS_SEARCH_LANGUAGE" envDefault:"english"`
+}
+
+type Moderation struct {
+	Mode             string        `env:"MODERATION_MODE" envDefault:"sync"`
+	BlockedWords     []string      `env:"MODERATION_BLOCKED_WORDS" envSeparator:","`
+	BlockedPatterns  []string      `env:"MODERATION_BLOCKED_PATTERNS" envSeparator:";"`
+	ImageBlocklist   []string      `env:"MODERATION_IMAGE_BLOCKLIST" envSeparator:","`
+	ImageMaxDistance int           `env:"MODERATION_IMAGE_MAX_DISTANCE" envDefault:"10"`
+	Timeout          time.Duration `env:"MODERATION_TIMEOUT" envDefault:"30s"`
+	// DuplicateMaxDistance is the perceptual hash distance under which photos
+	// of different users are flagged as duplicates, at most 4; negative disables
+	// the check.
+	DuplicateMaxDistance int `env:"MODERATION_DUPLICATE_MAX_DISTANCE" envDefault:"4"`
+}
+
+// Cache holds profiles and prompts read by full profiles and recommendations.
+// The lru backend is local to every instance and other instances only see
+// writes once values expire, so it only suits single instance deployments;
+// redis shares the cache between instances.
+type Cache struct {
+	Backend string        `env:"CACHE_BACKEND" envDefault:"none"`
+	TTL     time.Duration `env:"CACHE_TTL" envDefault:"1m"`
+	Size    int           `env:"CACHE_SIZE" envDefault:"10000"`
+
+	RedisAddress  string `env:"CACHE_REDIS_ADDRESS" example:"localhost:6379"`
+	RedisPassword string `env:"CACHE_REDIS_PASSWORD"`
+	RedisDB       int    `env:"CACHE_REDIS_DB" envDefault:"0"`
+	RedisPrefix   string `env:"CACHE_REDIS_PREFIX" envDefault:"profiles:"`
+}
+
+type Log struct {
+	Level        string   `env:"LOG_LEVEL" envDefault:"info"`
+	RedactFields []string `env:"LOG_REDACT_FIELDS" envSeparator:"," envDefault:"first_name,last_name,birth_date,location,content"`
+}
+
 type Config struct {
-	Postgres Postgres
-	API      API
-	Media    Media
-	Metrics  Metrics
+	Postgres   Postgres
+	API        API
+	Media      Media
+	MediaLinks MediaLinks
+	Metrics    Metrics
+	Log        Log
+	Prompts    Prompts
+	Moderation Moderation
+	Cache      Cache
 }
 
 func Load() (Config, error) {
@@ -46,5 +155,45 @@ func Load() (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("parsing config: %w", err)
 	}
+	err = cfg.Postgres.validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	err = cfg.MediaLinks.validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
 	return cfg, nil
 }
+
+// validate rejects durations that would make connecting to the database
+// give up at once or retry without pause, and backoff caps below the
+// backoff they cap.
+func (p Postgres) validate() error {
+	if p.ConnectionTimeout <= 0 {
+		return fmt.Errorf("POSTGRES_CONNECTION_TIMEOUT must be positive, got %s", p.ConnectionTimeout)
+	}
+	if p.RetryBackoff <= 0 {
+		return fmt.Errorf("POSTGRES_RETRY_BACKOFF must be positive, got %s", p.RetryBackoff)
+	}
+	if p.RetryMaxBackoff < p.RetryBackoff {
+		return fmt.Errorf("POSTGRES_RETRY_MAX_BACKOFF must not be less than POSTGRES_RETRY_BACKOFF, got %s < %s",
+			p.RetryMaxBackoff, p.RetryBackoff)
+	}
+	if p.TxRetryBackoff <= 0 {
+		return fmt.Errorf("POSTGRES_TX_RETRY_BACKOFF must be positive, got %s", p.TxRetryBackoff)
+	}
+	if p.TxRetryMaxBackoff < p.TxRetryBackoff {
+		return fmt.Errorf("POSTGRES_TX_RETRY_MAX_BACKOFF must not be less than POSTGRES_TX_RETRY_BACKOFF, got %s < %s",
+			p.TxRetryMaxBackoff, p.TxRetryBackoff)
+	}
+	return nil
+}
+
+// validate rejects a TTL that would hand out links that never expire.
+func (l MediaLinks) validate() error {
+	if l.TTL <= 0 {
+		return fmt.Errorf("MEDIA_LINKS_TTL must be positive, got %s", l.TTL)
+	}
+	return nil
+}
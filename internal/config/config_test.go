@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPostgresValidate(t *testing.T) {
+	valid := Postgres{
+		ConnectionTimeout: time.Minute,
+		RetryBackoff:      500 * time.Millisecond,
+		RetryMaxBackoff:   5 * time.Second,
+		TxRetryBackoff:    20 * time.Millisecond,
+		TxRetryMaxBackoff: 500 * time.Millisecond,
+	}
+	tests := []struct {
+		name    string
+		modify  func(p *Postgres)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *Postgres) {}},
+		{name: "zero connection timeout", modify: func(p *Postgres) { p.ConnectionTimeout = 0 }, wantErr: true},
+		{name: "negative connection timeout", modify: func(p *Postgres) { p.ConnectionTimeout = -time.Second }, wantErr: true},
+		{name: "zero retry backoff", modify: func(p *Postgres) { p.RetryBackoff = 0 }, wantErr: true},
+		{name: "negative retry backoff", modify: func(p *Postgres) { p.RetryBackoff = -time.Second }, wantErr: true},
+		{name: "equal retry backoffs", modify: func(p *Postgres) { p.RetryMaxBackoff = p.RetryBackoff }},
+		{name: "max retry backoff below retry backoff", modify: func(p *Postgres) { p.RetryMaxBackoff = time.Millisecond }, wantErr: true},
+		{name: "zero tx retry backoff", modify: func(p *Postgres) { p.TxRetryBackoff = 0 }, wantErr: true},
+		{name: "equal tx retry backoffs", modify: func(p *Postgres) { p.TxRetryMaxBackoff = p.TxRetryBackoff }},
+		{name: "max tx retry backoff below tx retry backoff", modify: func(p *Postgres) { p.TxRetryMaxBackoff = time.Millisecond }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			if err := p.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMediaLinksValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "valid", ttl: time.Hour},
+		{name: "zero ttl", ttl: 0, wantErr: true},
+		{name: "negative ttl", ttl: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := MediaLinks{BaseURL: "https://media.example.com", Secret: "secret", TTL: tt.ttl}
+			if err := l.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Block struct {
+	BlockerId uuid.UUID `db:"blocker_id"`
+	BlockedId uuid.UUID `db:"blocked_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
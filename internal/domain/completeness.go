@@ -0,0 +1,80 @@
+package domain
+
+// CompletenessItem is an item of the onboarding checklist.
+type CompletenessItem string
+
+const (
+	ItemLastName     CompletenessItem = "last_name"
+	ItemLocation     CompletenessItem = "location"
+	ItemHeight       CompletenessItem = "height"
+	ItemMainPicture  CompletenessItem = "main_picture"
+	ItemPhotos       CompletenessItem = "photos"
+	ItemTextPrompts  CompletenessItem = "text_prompts"
+	ItemMediaPrompts CompletenessItem = "media_prompts"
+)
+
+// Numbers of photos and text prompts of complete profiles.
+const (
+	CompletePhotos      = 3
+	CompleteTextPrompts = 3
+)
+
+// Completeness rates how filled in a profile is from 0 to 100 and lists the
+// checklist items left to complete, most valuable first.
+type Completeness struct {
+	Score   int
+	Missing []CompletenessItem
+}
+
+// NewCompleteness rates the profile with all its prompts, including the
+// gallery images. Rejected prompts and images do not count. The stored score
+// is computed by the repository and must follow the same rules.
+func NewCompleteness(p Profile, prompts []Prompt) Completeness {
+	var photos, texts, media int
+	for _, prompt := range prompts {
+		if prompt.ModerationStatus == ModerationRejected {
+			continue
+		}
+		switch prompt.Type {
+		case Image:
+			photos++
+		case Gallery:
+			for _, image := range prompt.Images {
+				if image.ModerationStatus != ModerationRejected {
+					photos++
+				}
+			}
+		case Text:
+			texts++
+		case Audio, Video:
+			media++
+		}
+	}
+
+	var c Completeness
+	c.add(ItemPhotos, 30, photos, CompletePhotos)
+	c.add(ItemTextPrompts, 25, texts, CompleteTextPrompts)
+	c.check(ItemMainPicture, 15, p.MainPicPromptID != nil)
+	c.add(ItemMediaPrompts, 10, media, 1)
+	c.check(ItemLocation, 10, p.Location != "")
+	c.check(ItemLastName, 5, p.LastName != "")
+	c.check(ItemHeight, 5, p.Height > 0)
+	return c
+}
+
+// add scores the item in proportion to how much of it is done.
+func (c *Completeness) add(item CompletenessItem, weight int, done int, required int) {
+	c.Score += weight * min(done, required) / required
+	if done < required {
+		c.Missing = append(c.Missing, item)
+	}
+}
+
+// check scores an item that is either done or not.
+func (c *Completeness) check(item CompletenessItem, weight int, done bool) {
+	if done {
+		c.Score += weight
+	} else {
+		c.Missing = append(c.Missing, item)
+	}
+}
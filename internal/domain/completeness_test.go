@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewCompleteness(t *testing.T) {
+	mainPic := uuid.New()
+	complete := Profile{LastName: "Doe", Location: "Berlin", Height: 180, MainPicPromptID: &mainPic}
+	prompts := func(types ...ContentType) []Prompt {
+		res := make([]Prompt, len(types))
+		for i, typ := range types {
+			res[i] = Prompt{Type: typ, ModerationStatus: ModerationApproved}
+		}
+		return res
+	}
+
+	tests := []struct {
+		name        string
+		profile     Profile
+		prompts     []Prompt
+		wantScore   int
+		wantMissing []CompletenessItem
+	}{
+		{
+			name:      "empty profile",
+			wantScore: 0,
+			wantMissing: []CompletenessItem{
+				ItemPhotos, ItemTextPrompts, ItemMainPicture, ItemMediaPrompts, ItemLocation, ItemLastName, ItemHeight,
+			},
+		},
+		{
+			name:        "complete profile",
+			profile:     complete,
+			prompts:     prompts(Image, Image, Image, Text, Text, Text, Audio),
+			wantScore:   100,
+			wantMissing: nil,
+		},
+		{
+			name:        "partial photos and texts are scored proportionally",
+			profile:     complete,
+			prompts:     prompts(Image, Text, Text, Video),
+			wantScore:   10 + 16 + 15 + 10 + 10 + 5 + 5,
+			wantMissing: []CompletenessItem{ItemPhotos, ItemTextPrompts},
+		},
+		{
+			name:        "extra prompts do not raise the score",
+			profile:     complete,
+			prompts:     prompts(Image, Image, Image, Image, Text, Text, Text, Text, Audio, Video),
+			wantScore:   100,
+			wantMissing: nil,
+		},
+		{
+			name:    "gallery images count as photos",
+			profile: complete,
+			prompts: append(prompts(Text, Text, Text, Audio), Prompt{
+				Type:             Gallery,
+				ModerationStatus: ModerationApproved,
+				Images: []GalleryImage{
+					{ModerationStatus: ModerationApproved},
+					{ModerationStatus: ModerationPending},
+					{ModerationStatus: ModerationRejected},
+				},
+			}),
+			wantScore:   20 + 25 + 15 + 10 + 10 + 5 + 5,
+			wantMissing: []CompletenessItem{ItemPhotos},
+		},
+		{
+			name:    "rejected prompts do not count",
+			profile: complete,
+			prompts: append(prompts(Image, Image, Image, Text, Text, Text),
+				Prompt{Type: Audio, ModerationStatus: ModerationRejected},
+				Prompt{Type: Gallery, ModerationStatus: ModerationRejected, Images: []GalleryImage{
+					{ModerationStatus: ModerationApproved},
+				}},
+			),
+			wantScore:   90,
+			wantMissing: []CompletenessItem{ItemMediaPrompts},
+		},
+		{
+			name:        "profile fields",
+			profile:     Profile{Location: "Berlin", Height: 170},
+			wantScore:   15,
+			wantMissing: []CompletenessItem{ItemPhotos, ItemTextPrompts, ItemMainPicture, ItemMediaPrompts, ItemLastName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewCompleteness(tt.profile, tt.prompts)
+			if got.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", got.Score, tt.wantScore)
+			}
+			if !slices.Equal(got.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", got.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
@@ -9,6 +9,49 @@ var (
 	ErrForbidden                = errors.New("forbidden")
 	ErrIDAlreadyExists          = errors.New("id already exists")
 	ErrNotUnique                = errors.New("entity is not unique")
+	ErrStillReferenced          = errors.New("entity is still referenced")
 	ErrAddPromptsOnEmptyProfile = errors.New("create profile before adding prompts")
-	ErrCannotDeleteProfilePic   = errors.New("cannot delete profile picture")
+	ErrCannotBlockSelf          = errors.New("cannot block yourself")
+	ErrCannotReportSelf         = errors.New("cannot report yourself")
+	ErrReportNotOpen            = errors.New("report is not open")
+	ErrReportNotClaimed         = errors.New("report is not claimed by the moderator")
+	ErrReportWithoutPrompt      = errors.New("report does not reference a prompt")
+	ErrQuestionInactive         = errors.New("prompt question is not active")
+	ErrQuestionInUse            = errors.New("prompt question is used by prompts")
+	ErrQuestionTextTaken        = errors.New("prompt question text is used by other prompts of the same users")
+	ErrContentTypeNotAllowed    = errors.New("content type is not allowed for the question")
+	ErrTooManyPrompts           = errors.New("too many prompts of this type")
+	ErrInvalidPromptLayout      = errors.New("invalid prompt positions")
+	ErrNotImagePrompt           = errors.New("prompt is not an image")
+	ErrImageNotApproved         = errors.New("image is not approved by moderation")
+	ErrContentRejected          = errors.New("content rejected by moderation")
+	ErrUnsupportedMedia         = errors.New("unsupported media format or codec")
+	ErrMediaTooLong             = errors.New("media duration is out of range")
+	ErrPromptTypeChanged        = errors.New("prompt type cannot be changed")
+	ErrFileRequired             = errors.New("prompt content can only be replaced with a file")
+	ErrNotGalleryPrompt         = errors.New("prompt is not a gallery")
+	ErrTooManyGalleryImages     = errors.New("too many images in the gallery")
+	ErrEmptySearchQuery         = errors.New("search query is empty")
+	ErrBatchTooLarge            = errors.New("too many profiles requested at once")
 )
+
+// ValidationError reports a value rejected by a database constraint or type.
+type ValidationError struct {
+	Constraint string
+	Column     string
+	Reason     string
+}
+
+func (e *ValidationError) Error() string {
+	msg := "invalid value"
+	if e.Column != "" {
+		msg += " of " + e.Column
+	}
+	if e.Constraint != "" {
+		msg += " (" + e.Constraint + ")"
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
@@ -1,6 +1,17 @@
 package domain
 
+import "github.com/google/uuid"
+
 type FullProfile struct {
 	Profile Profile
 	Prompts []Prompt
+	// Completeness is only computed for the owner of the profile.
+	Completeness *Completeness
+}
+
+// FullProfiles holds the full profiles of a batch in request order and the
+// requested IDs of profiles that do not exist or are hidden from the viewer.
+type FullProfiles struct {
+	Profiles   []FullProfile
+	MissingIds []uuid.UUID
 }
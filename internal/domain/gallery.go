@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GalleryImage is one of the ordered images of a gallery prompt.
+type GalleryImage struct {
+	ID               uuid.UUID        `db:"id"`
+	PromptId         uuid.UUID        `db:"prompt_id"`
+	Link             string           `db:"link"`
+	Caption          string           `db:"caption"`
+	Position         int32            `db:"position"`
+	ModerationStatus ModerationStatus `db:"moderation_status"`
+	CreatedAt        time.Time        `db:"created_at"`
+	Renditions       Renditions       `db:"renditions"`
+}
+
+// GalleryUpload is an image added to a gallery prompt.
+type GalleryUpload struct {
+	UserId   uuid.UUID
+	PromptId uuid.UUID
+	Content  []byte `validate:"required"`
+	Caption  string `validate:"max=200"`
+	Position int32  `validate:"min=0"`
+}
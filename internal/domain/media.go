@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// MediaInfo describes an uploaded audio or video file.
+type MediaInfo struct {
+	MimeType string
+	Codecs   []string
+	HasVideo bool
+	Duration time.Duration
+}
+
+// MediaLimits restricts the codecs and the duration of audio and video prompts.
+type MediaLimits struct {
+	AudioCodecs      []string
+	VideoCodecs      []string
+	MaxAudioDuration time.Duration
+	MaxVideoDuration time.Duration
+}
+
+// Check reports whether the file is acceptable content for a prompt of the given type.
+func (l MediaLimits) Check(t ContentType, info MediaInfo) error {
+	var codecs []string
+	var maxDuration time.Duration
+	switch t {
+	case Audio:
+		if info.HasVideo {
+			return ErrUnsupportedMedia
+		}
+		codecs, maxDuration = l.AudioCodecs, l.MaxAudioDuration
+	case Video:
+		if !info.HasVideo {
+			return ErrUnsupportedMedia
+		}
+		codecs, maxDuration = l.VideoCodecs, l.MaxVideoDuration
+	default:
+		return ErrUnsupportedMedia
+	}
+	for _, c := range info.Codecs {
+		if !slices.Contains(codecs, c) {
+			return ErrUnsupportedMedia
+		}
+	}
+	if info.Duration <= 0 || info.Duration > maxDuration {
+		return ErrMediaTooLong
+	}
+	return nil
+}
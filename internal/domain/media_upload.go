@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaUpload records a file a user uploaded to the media service, so that
+// uploading the same content again reuses the stored file.
+type MediaUpload struct {
+	UserId         uuid.UUID  `db:"user_id"`
+	Sha256         []byte     `db:"sha256"`
+	PerceptualHash *int64     `db:"perceptual_hash"`
+	Link           string     `db:"link"`
+	CreatedAt      time.Time  `db:"created_at"`
+	Renditions     Renditions `db:"renditions"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "github.com/google/uuid"
+
+type ModerationStatus string
+
+const (
+	ModerationApproved ModerationStatus = "approved"
+	ModerationPending  ModerationStatus = "pending"
+	ModerationRejected ModerationStatus = "rejected"
+)
+
+// VisibleTo reports whether the prompt content can be shown to the viewer:
+// owners see all of their prompts, other users only approved ones.
+func (p Prompt) VisibleTo(viewerId uuid.UUID) bool {
+	return p.UserId == viewerId || p.ModerationStatus == ModerationApproved
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ProfileFilter selects the profiles listed for admin tools. Zero fields
+// do not filter.
+type ProfileFilter struct {
+	Sex           string `validate:"omitempty,oneof=man woman"`
+	MinAge        int    `validate:"min=0"`
+	MaxAge        int    `validate:"omitempty,gtefield=MinAge"`
+	Location      string
+	Intention     string `validate:"omitempty,oneof='life partner' 'long-term relationship' 'short-term relationship' 'friendship' 'figuring it out' 'prefer not to say'"`
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	HasPhotos     *bool
+}
+
+// ProfileCursor points after the last profile of a page. Profiles are
+// listed from the newest, with the user ID breaking ties.
+type ProfileCursor struct {
+	CreatedAt time.Time
+	UserId    uuid.UUID
+}
+
+func NewProfileCursor(p Profile) *ProfileCursor {
+	return &ProfileCursor{CreatedAt: p.CreatedAt, UserId: p.UserId}
+}
+
+// Encode returns the cursor as an opaque token.
+func (c ProfileCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.UserId.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseProfileCursor decodes a token returned by Encode. An empty token is
+// the start of the listing.
+func ParseProfileCursor(token string) (*ProfileCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, userId, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	c := &ProfileCursor{}
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.UserId, err = uuid.Parse(userId); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// ProfilePage is a page of listed profiles. Next is nil on the last page.
+type ProfilePage struct {
+	Profiles []Profile
+	Next     *ProfileCursor
+}
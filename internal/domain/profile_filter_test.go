@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestProfileCursorRoundTrip(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	tests := []struct {
+		name   string
+		cursor ProfileCursor
+	}{
+		{name: "utc", cursor: ProfileCursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), UserId: uuid.New()}},
+		{name: "nanoseconds", cursor: ProfileCursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC), UserId: uuid.New()}},
+		{name: "other time zone", cursor: ProfileCursor{CreatedAt: time.Date(2024, 5, 1, 14, 0, 0, 0, berlin), UserId: uuid.New()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfileCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.UserId != tt.cursor.UserId {
+				t.Errorf("ParseProfileCursor() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParseProfileCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	id := uuid.New()
+	tests := []struct {
+		name    string
+		token   string
+		wantNil bool
+		wantErr error
+	}{
+		{name: "empty starts the listing", token: "", wantNil: true},
+		{name: "valid", token: encode("2024-05-01T12:00:00Z|" + id.String())},
+		{name: "not base64", token: "!!!", wantErr: ErrInvalidCursor},
+		{name: "missing separator", token: encode("2024-05-01T12:00:00Z"), wantErr: ErrInvalidCursor},
+		{name: "invalid time", token: encode("yesterday|" + id.String()), wantErr: ErrInvalidCursor},
+		{name: "invalid user id", token: encode("2024-05-01T12:00:00Z|nobody"), wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfileCursor(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseProfileCursor() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got == nil) != tt.wantNil {
+				t.Errorf("ParseProfileCursor() = %v, want nil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
@@ -5,24 +5,49 @@ import "github.com/google/uuid"
 type ContentType string
 
 const (
-	Image ContentType = "image"
-	Text  ContentType = "text"
+	Image   ContentType = "image"
+	Text    ContentType = "text"
+	Audio   ContentType = "audio"
+	Video   ContentType = "video"
+	Gallery ContentType = "gallery"
 )
 
+// IsMedia reports whether prompts of the type hold an audio or video file.
+func (t ContentType) IsMedia() bool {
+	return t == Audio || t == Video
+}
+
+// HasFile reports whether the content of prompts of the type is a media object key.
+func (t ContentType) HasFile() bool {
+	return t != Text
+}
+
 type Prompt struct {
-	ID       uuid.UUID   `db:"id"`
-	UserId   uuid.UUID   `db:"user_id"`
-	Question string      `db:"question"`
-	Content  string      `db:"content"`
-	Position int32       `db:"position" validate:"min=0,max=10"`
-	Type     ContentType `db:"type" validate:"oneof=image text"`
+	ID         uuid.UUID   `db:"id"`
+	UserId     uuid.UUID   `db:"user_id"`
+	Question   string      `db:"question"`
+	Content    string      `db:"content"`
+	Position   int32       `db:"position" validate:"min=0"`
+	Type       ContentType `db:"type" validate:"oneof=image text audio video gallery"`
+	QuestionId *uuid.UUID  `db:"question_id"`
+
+	ModerationStatus ModerationStatus `db:"moderation_status"`
+	DurationMs       int32            `db:"duration_ms"`
+	ThumbnailLink    string           `db:"thumbnail_link"`
+	// Renditions holds the scaled down copies of image prompts.
+	Renditions Renditions `db:"renditions"`
+	// Images holds the images of gallery prompts.
+	Images []GalleryImage `db:"-"`
 }
 
 type FilePrompt struct {
-	ID       uuid.UUID   `db:"id"`
-	UserId   uuid.UUID   `db:"user_id"`
-	Question string      `db:"question"`
-	Content  []byte      `db:"content"`
-	Position int32       `db:"position"`
-	Type     ContentType `db:"type"  validate:"oneof=image text"`
+	ID         uuid.UUID   `db:"id"`
+	UserId     uuid.UUID   `db:"user_id"`
+	Question   string      `db:"question"`
+	Content    []byte      `db:"content"`
+	Position   int32       `db:"position"`
+	Type       ContentType `db:"type"  validate:"oneof=image text audio video"`
+	QuestionId *uuid.UUID  `db:"question_id"`
+	// Thumbnail is an optional poster image of a video prompt.
+	Thumbnail []byte `db:"-"`
 }
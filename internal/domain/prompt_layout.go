@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// PromptLimits bounds the number of prompts a profile may hold.
+type PromptLimits struct {
+	MaxText    int
+	MaxImage   int
+	MaxAudio   int
+	MaxVideo   int
+	MaxGallery int
+}
+
+// Check reports whether the added prompts fit into the limits next to the
+// existing ones. Only the types being added are checked, so that profiles
+// already over the limit of one type can still add prompts of other types.
+func (l PromptLimits) Check(existing []Prompt, added []Prompt) error {
+	limits := map[ContentType]int{
+		Text: l.MaxText, Image: l.MaxImage, Audio: l.MaxAudio, Video: l.MaxVideo, Gallery: l.MaxGallery,
+	}
+	counts := make(map[ContentType]int)
+	for _, p := range added {
+		counts[p.Type]++
+	}
+	for _, p := range existing {
+		if _, ok := counts[p.Type]; ok {
+			counts[p.Type]++
+		}
+	}
+	for typ, count := range counts {
+		if count > limits[typ] {
+			return ErrTooManyPrompts
+		}
+	}
+	return nil
+}
+
+// LayoutPrompts places the moved prompts at their requested positions
+// among the other prompts, which keep their relative order, and renumbers
+// the result to consecutive positions starting at zero.
+// Requested positions past the end are moved to the end.
+func LayoutPrompts(others []Prompt, moved []Prompt) ([]Prompt, error) {
+	seen := make(map[int32]struct{}, len(moved))
+	for _, p := range moved {
+		if p.Position < 0 {
+			return nil, ErrInvalidPromptLayout
+		}
+		if _, ok := seen[p.Position]; ok {
+			return nil, ErrInvalidPromptLayout
+		}
+		seen[p.Position] = struct{}{}
+	}
+
+	res := make([]Prompt, len(others), len(others)+len(moved))
+	copy(res, others)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Position < res[j].Position
+	})
+
+	sorted := make([]Prompt, len(moved))
+	copy(sorted, moved)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+	for _, p := range sorted {
+		i := min(int(p.Position), len(res))
+		res = append(res[:i], append([]Prompt{p}, res[i:]...)...)
+	}
+
+	for i := range res {
+		res[i].Position = int32(i)
+	}
+	return res, nil
+}
+
+// ChangedPositions returns the prompts of the layout whose position differs from
+// the original one. Prompts missing from the originals are skipped.
+func ChangedPositions(original []Prompt, layout []Prompt) []Prompt {
+	positions := make(map[uuid.UUID]int32, len(original))
+	for _, p := range original {
+		positions[p.ID] = p.Position
+	}
+	var res []Prompt
+	for _, p := range layout {
+		if pos, ok := positions[p.ID]; ok && pos != p.Position {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func samePosition(a, b Prompt) bool {
+	return a.ID == b.ID && a.Position == b.Position
+}
+
+func TestLayoutPrompts(t *testing.T) {
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	at := func(i int, position int32) Prompt {
+		return Prompt{ID: ids[i], Position: position}
+	}
+
+	tests := []struct {
+		name    string
+		others  []Prompt
+		moved   []Prompt
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "gaps are compacted",
+			others: []Prompt{at(0, 3), at(1, 7), at(2, 12)},
+			want:   []uuid.UUID{ids[0], ids[1], ids[2]},
+		},
+		{
+			name:   "others are sorted by position",
+			others: []Prompt{at(2, 2), at(0, 0), at(1, 1)},
+			want:   []uuid.UUID{ids[0], ids[1], ids[2]},
+		},
+		{
+			name:   "moved to the front",
+			others: []Prompt{at(0, 0), at(1, 1), at(2, 2)},
+			moved:  []Prompt{at(3, 0)},
+			want:   []uuid.UUID{ids[3], ids[0], ids[1], ids[2]},
+		},
+		{
+			name:   "moved into the middle",
+			others: []Prompt{at(0, 0), at(1, 1), at(2, 2)},
+			moved:  []Prompt{at(3, 2)},
+			want:   []uuid.UUID{ids[0], ids[1], ids[3], ids[2]},
+		},
+		{
+			name:   "positions past the end are moved to the end",
+			others: []Prompt{at(0, 0), at(1, 1)},
+			moved:  []Prompt{at(3, 10), at(4, 5)},
+			want:   []uuid.UUID{ids[0], ids[1], ids[4], ids[3]},
+		},
+		{
+			name:   "several moved prompts are placed in order",
+			others: []Prompt{at(0, 0), at(1, 1), at(2, 2)},
+			moved:  []Prompt{at(4, 3), at(3, 0)},
+			want:   []uuid.UUID{ids[3], ids[0], ids[1], ids[4], ids[2]},
+		},
+		{
+			name:  "only moved prompts",
+			moved: []Prompt{at(1, 1), at(0, 0)},
+			want:  []uuid.UUID{ids[0], ids[1]},
+		},
+		{
+			name:    "negative position",
+			others:  []Prompt{at(0, 0)},
+			moved:   []Prompt{at(1, -1)},
+			wantErr: ErrInvalidPromptLayout,
+		},
+		{
+			name:    "duplicate positions",
+			others:  []Prompt{at(0, 0)},
+			moved:   []Prompt{at(1, 1), at(2, 1)},
+			wantErr: ErrInvalidPromptLayout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			others := slices.Clone(tt.others)
+			got, err := LayoutPrompts(tt.others, tt.moved)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LayoutPrompts() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(tt.others, others, samePosition) {
+				t.Errorf("LayoutPrompts() modified others")
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			gotIds := make([]uuid.UUID, len(got))
+			for i, p := range got {
+				gotIds[i] = p.ID
+				if p.Position != int32(i) {
+					t.Errorf("prompt %d has position %d", i, p.Position)
+				}
+			}
+			if !slices.Equal(gotIds, tt.want) {
+				t.Errorf("LayoutPrompts() = %v, want %v", gotIds, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangedPositions(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		name     string
+		original []Prompt
+		layout   []Prompt
+		want     []Prompt
+	}{
+		{
+			name:     "unchanged",
+			original: []Prompt{{ID: a, Position: 0}, {ID: b, Position: 1}},
+			layout:   []Prompt{{ID: a, Position: 0}, {ID: b, Position: 1}},
+		},
+		{
+			name:     "swapped",
+			original: []Prompt{{ID: a, Position: 0}, {ID: b, Position: 1}},
+			layout:   []Prompt{{ID: b, Position: 0}, {ID: a, Position: 1}},
+			want:     []Prompt{{ID: b, Position: 0}, {ID: a, Position: 1}},
+		},
+		{
+			name:     "new prompts are skipped",
+			original: []Prompt{{ID: a, Position: 3}},
+			layout:   []Prompt{{ID: c, Position: 0}, {ID: a, Position: 1}},
+			want:     []Prompt{{ID: a, Position: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChangedPositions(tt.original, tt.layout)
+			if !slices.EqualFunc(got, tt.want, samePosition) {
+				t.Errorf("ChangedPositions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptLimitsCheck(t *testing.T) {
+	limits := PromptLimits{MaxText: 2, MaxImage: 1, MaxAudio: 1, MaxVideo: 0, MaxGallery: 1}
+	prompts := func(types ...ContentType) []Prompt {
+		res := make([]Prompt, len(types))
+		for i, typ := range types {
+			res[i] = Prompt{Type: typ}
+		}
+		return res
+	}
+	tests := []struct {
+		name     string
+		existing []Prompt
+		added    []Prompt
+		wantErr  error
+	}{
+		{name: "empty"},
+		{name: "at the limits", existing: prompts(Text, Image), added: prompts(Text, Audio, Gallery)},
+		{name: "too many texts", existing: prompts(Text, Text), added: prompts(Text), wantErr: ErrTooManyPrompts},
+		{name: "too many texts added at once", added: prompts(Text, Text, Text), wantErr: ErrTooManyPrompts},
+		{name: "disallowed video", added: prompts(Video), wantErr: ErrTooManyPrompts},
+		{name: "too many galleries", existing: prompts(Gallery), added: prompts(Gallery), wantErr: ErrTooManyPrompts},
+		{
+			name:     "types over the limit do not block other types",
+			existing: prompts(Text, Text, Text, Text, Video),
+			added:    prompts(Image),
+		},
+		{
+			name:     "types over the limit cannot grow",
+			existing: prompts(Text, Text, Text, Text),
+			added:    prompts(Text),
+			wantErr:  ErrTooManyPrompts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := limits.Check(tt.existing, tt.added); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"sort"
+	"time"
+)
+
+// DefaultLocale is the locale whose text is stored in Prompt.Question
+// for prompts referencing the catalog.
+const DefaultLocale = "en"
+
+// PromptQuestion is a question from the server-managed prompt catalog.
+type PromptQuestion struct {
+	ID           uuid.UUID         `db:"id"`
+	Texts        map[string]string `db:"texts" validate:"required,dive,keys,required,endkeys,required"`
+	Category     string            `db:"category"`
+	ContentTypes []ContentType     `db:"content_types" validate:"required,dive,oneof=image text"`
+	Active       bool              `db:"active"`
+	CreatedAt    time.Time         `db:"created_at"`
+}
+
+// Text returns the question text in the given locale, falling back to
+// the default locale and then to any available translation.
+func (q PromptQuestion) Text(locale string) string {
+	if text, ok := q.Texts[locale]; ok {
+		return text
+	}
+	if text, ok := q.Texts[DefaultLocale]; ok {
+		return text
+	}
+	locales := make([]string, 0, len(q.Texts))
+	for l := range q.Texts {
+		locales = append(locales, l)
+	}
+	if len(locales) == 0 {
+		return ""
+	}
+	sort.Strings(locales)
+	return q.Texts[locales[0]]
+}
+
+func (q PromptQuestion) Allows(t ContentType) bool {
+	return slices.Contains(q.ContentTypes, t)
+}
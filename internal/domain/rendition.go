@@ -0,0 +1,14 @@
+package domain
+
+// Renditions maps rendition names, such as thumbnail or card, to the
+// object keys of the scaled down copies of an image.
+type Renditions map[string]string
+
+// Pick returns the key of the named rendition, or fallback when the image
+// has no such rendition.
+func (r Renditions) Pick(name, fallback string) string {
+	if key, ok := r[name]; ok && key != "" {
+		return key
+	}
+	return fallback
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ReportReason string
+
+const (
+	ReasonFakeProfile          ReportReason = "fake profile"
+	ReasonInappropriateContent ReportReason = "inappropriate content"
+	ReasonHarassment           ReportReason = "harassment"
+	ReasonSpam                 ReportReason = "spam"
+	ReasonUnderage             ReportReason = "underage"
+	ReasonOther                ReportReason = "other"
+	// ReasonDuplicatePhoto is only used by reports raised by the service.
+	ReasonDuplicatePhoto ReportReason = "duplicate photo"
+)
+
+type ReportStatus string
+
+const (
+	ReportOpen     ReportStatus = "open"
+	ReportClaimed  ReportStatus = "claimed"
+	ReportResolved ReportStatus = "resolved"
+)
+
+type ModerationAction string
+
+const (
+	ActionDismiss        ModerationAction = "dismiss"
+	ActionRemovePrompt   ModerationAction = "remove prompt"
+	ActionSuspendProfile ModerationAction = "suspend profile"
+)
+
+type Report struct {
+	ID             uuid.UUID         `db:"id"`
+	ReporterId     *uuid.UUID        `db:"reporter_id"`
+	ReportedId     uuid.UUID         `db:"reported_id"`
+	Reason         ReportReason      `db:"reason" validate:"oneof='fake profile' 'inappropriate content' 'harassment' 'spam' 'underage' 'other'"`
+	PromptId       *uuid.UUID        `db:"prompt_id"`
+	Comment        string            `db:"comment" validate:"max=2000"`
+	Status         ReportStatus      `db:"status"`
+	ModeratorId    *uuid.UUID        `db:"moderator_id"`
+	Action         *ModerationAction `db:"action"`
+	ResolutionNote string            `db:"resolution_note"`
+	CreatedAt      time.Time         `db:"created_at"`
+	ClaimedAt      *time.Time        `db:"claimed_at"`
+	ResolvedAt     *time.Time        `db:"resolved_at"`
+}
+
+// Resolution is the moderator decision on a claimed report.
+type Resolution struct {
+	ReportId    uuid.UUID
+	ModeratorId uuid.UUID
+	Action      ModerationAction `validate:"oneof='dismiss' 'remove prompt' 'suspend profile'"`
+	Note        string           `validate:"max=2000"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+// PromptMatch is a prompt found by a search with the matched words
+// highlighted in its snippet.
+type PromptMatch struct {
+	Prompt
+	Snippet string `db:"snippet"`
+}
+
+// PromptSearchResult holds the matched prompts in relevance order and the
+// profiles that own them.
+type PromptSearchResult struct {
+	Matches  []PromptMatch
+	Profiles []Profile
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Visibility controls who can discover a profile besides its owner.
+type Visibility string
+
+const (
+	// Visible profiles are shown to everyone.
+	Visible Visibility = "visible"
+	// Paused profiles are hidden from everyone.
+	Paused Visibility = "paused"
+	// Incognito profiles are shown only to explicitly allowed viewers.
+	Incognito Visibility = "incognito"
+)
+
+type AllowedViewer struct {
+	UserId    uuid.UUID `db:"user_id"`
+	ViewerId  uuid.UUID `db:"viewer_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
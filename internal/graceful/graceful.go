@@ -3,7 +3,7 @@ package graceful
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,7 +15,7 @@ func CaptureSignal(ctx context.Context, sigQuit chan os.Signal) func() error {
 	return func() error {
 		select {
 		case s := <-sigQuit:
-			log.Printf("captured signal: %v\n", s)
+			slog.Info("captured signal", "signal", s.String())
 			return fmt.Errorf("captured signal: %v", s)
 		case <-ctx.Done():
 			return nil
@@ -0,0 +1,112 @@
+// Package imaging scales uploaded photos down to the configured renditions.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+const jpegQuality = 85
+
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// Size is a rendition whose images fit in a MaxSide x MaxSide square.
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// ParseSizes parses sizes written as name:max_side, e.g. thumbnail:160.
+func ParseSizes(values []string) ([]Size, error) {
+	sizes := make([]Size, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		name, side, ok := strings.Cut(v, ":")
+		if !ok {
+			return nil, fmt.Errorf("rendition %q: expected name:max_side", v)
+		}
+		maxSide, err := strconv.Atoi(side)
+		if err != nil || maxSide <= 0 {
+			return nil, fmt.Errorf("rendition %q: invalid max side", v)
+		}
+		sizes = append(sizes, Size{Name: name, MaxSide: maxSide})
+	}
+	return sizes, nil
+}
+
+// Image is a decoded photo that can be scaled to several sizes.
+type Image struct {
+	img image.Image
+}
+
+func Decode(data []byte, maxPixels int) (*Image, error) {
+	img, err := DecodeImage(data, maxPixels)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{img: img}, nil
+}
+
+// DecodeImage decodes an image of at most maxPixels pixels. The dimensions
+// are read from the header first, so that small files declaring huge images
+// are refused before their pixels are allocated.
+func DecodeImage(data []byte, maxPixels int) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image config: %w", err)
+	}
+	if int64(config.Width)*int64(config.Height) > int64(maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	return img, nil
+}
+
+// MimeType returns the MIME type of the image format detected from the header
+// of data, e.g. image/jpeg.
+func MimeType(data []byte) (string, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("decode image config: %w", err)
+	}
+	return "image/" + format, nil
+}
+
+// Fits reports whether the image already fits in the size.
+func (i *Image) Fits(size Size) bool {
+	b := i.img.Bounds()
+	return b.Dx() <= size.MaxSide && b.Dy() <= size.MaxSide
+}
+
+// Resize scales the image down to fit in the size, keeping its aspect
+// ratio, and encodes it as JPEG.
+func (i *Image) Resize(size Size) ([]byte, error) {
+	b := i.img.Bounds()
+	scale := min(float64(size.MaxSide)/float64(b.Dx()), float64(size.MaxSide)/float64(b.Dy()), 1)
+	width := max(int(float64(b.Dx())*scale), 1)
+	height := max(int(float64(b.Dy())*scale), 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), i.img, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"testing"
+)
+
+func TestParseSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []Size
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name:   "several sizes",
+			values: []string{"thumbnail:160", " card:640 ", "", "full:1600"},
+			want:   []Size{{Name: "thumbnail", MaxSide: 160}, {Name: "card", MaxSide: 640}, {Name: "full", MaxSide: 1600}},
+		},
+		{name: "missing side", values: []string{"thumbnail"}, wantErr: true},
+		{name: "invalid side", values: []string{"thumbnail:big"}, wantErr: true},
+		{name: "zero side", values: []string{"thumbnail:0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSizes(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSizes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseSizes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMimeTypeAndDecode(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 30))
+	var pngData, jpegData bytes.Buffer
+	if err := png.Encode(&pngData, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		maxPixels int
+		wantType  string
+		wantErr   error
+	}{
+		{name: "png", data: pngData.Bytes(), maxPixels: 1200, wantType: "image/png"},
+		{name: "jpeg", data: jpegData.Bytes(), maxPixels: 1200, wantType: "image/jpeg"},
+		{name: "too many pixels", data: pngData.Bytes(), maxPixels: 1199, wantType: "image/png", wantErr: ErrTooManyPixels},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, err := MimeType(tt.data)
+			if err != nil || mimeType != tt.wantType {
+				t.Errorf("MimeType() = %q, %v, want %q", mimeType, err, tt.wantType)
+			}
+			_, err = Decode(tt.data, tt.maxPixels)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := MimeType([]byte("not an image")); err == nil {
+		t.Error("MimeType() accepted an undecodable image")
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/soulmate-dating/profiles/internal/requestid"
+)
+
+const redacted = "[REDACTED]"
+
+type Config struct {
+	Level        string
+	RedactFields []string
+}
+
+// New creates a JSON logger that annotates records with the request ID
+// stored in the context and masks the values of personal fields.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("parse log level: %w", err)
+	}
+
+	redact := make(map[string]struct{}, len(cfg.RedactFields))
+	for _, f := range cfg.RedactFields {
+		redact[f] = struct{}{}
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(&contextHandler{Handler: handler, redact: redact}), nil
+}
+
+// contextHandler redacts the attributes itself, as ReplaceAttr is not
+// called for groups and would log a group whose key is redacted.
+type contextHandler struct {
+	slog.Handler
+	redact map[string]struct{}
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		record.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &contextHandler{Handler: h.Handler.WithAttrs(redacted), redact: h.redact}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name), redact: h.redact}
+}
+
+// redactAttr masks the value of the attribute if its key is redacted and
+// the values of the redacted keys nested in groups.
+func (h *contextHandler) redactAttr(a slog.Attr) slog.Attr {
+	if _, ok := h.redact[a.Key]; ok {
+		return slog.String(a.Key, redacted)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+	group := a.Value.Group()
+	attrs := make([]slog.Attr, len(group))
+	for i, member := range group {
+		attrs[i] = h.redactAttr(member)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
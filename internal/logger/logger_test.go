@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/soulmate-dating/profiles/internal/requestid"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		log  func(log *slog.Logger)
+		// want holds the expected fields; nil expects nothing to be logged.
+		want map[string]any
+	}{
+		{
+			name: "personal fields are redacted",
+			log: func(log *slog.Logger) {
+				log.InfoContext(ctx, "profile created", "first_name", "Jane", "location", "Berlin", "height", 170)
+			},
+			want: map[string]any{"msg": "profile created", "first_name": redacted, "location": redacted, "height": 170.0},
+		},
+		{
+			name: "personal fields in groups are redacted",
+			log: func(log *slog.Logger) {
+				log.InfoContext(ctx, "profile created",
+					slog.Group("profile", "first_name", "Jane", slog.Group("address", "location", "Berlin")),
+					slog.Group("location", "city", "Berlin"),
+				)
+			},
+			want: map[string]any{
+				"msg": "profile created",
+				"profile": map[string]any{
+					"first_name": redacted,
+					"address":    map[string]any{"location": redacted},
+				},
+				"location": redacted,
+			},
+		},
+		{
+			name: "personal fields in attributes are redacted",
+			log: func(log *slog.Logger) {
+				log.With(slog.Group("profile", "first_name", "Jane")).WithGroup("request").InfoContext(ctx, "request", "location", "Berlin")
+			},
+			want: map[string]any{
+				"msg":     "request",
+				"profile": map[string]any{"first_name": redacted},
+				"request": map[string]any{"location": redacted},
+			},
+		},
+		{
+			name: "request id is added",
+			log: func(log *slog.Logger) {
+				log.InfoContext(requestid.NewContext(ctx, "req-1"), "request")
+			},
+			want: map[string]any{"msg": "request", "request_id": "req-1"},
+		},
+		{
+			name: "request id is kept with attributes",
+			log: func(log *slog.Logger) {
+				log.With("method", "GetProfile").InfoContext(requestid.NewContext(ctx, "req-2"), "request")
+			},
+			want: map[string]any{"msg": "request", "request_id": "req-2", "method": "GetProfile"},
+		},
+		{
+			name: "records below the level are dropped",
+			log: func(log *slog.Logger) {
+				log.DebugContext(ctx, "hidden")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log, err := New(&buf, Config{Level: "info", RedactFields: []string{"first_name", "location"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.log(log)
+			if tt.want == nil {
+				if buf.Len() != 0 {
+					t.Errorf("logged %s, want nothing", buf.String())
+				}
+				return
+			}
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("decode %s: %v", buf.String(), err)
+			}
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(got[key], want) {
+					t.Errorf("%s = %v, want %v", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidLevel(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, Config{Level: "verbose"}); err == nil {
+		t.Error("New() accepted an invalid level")
+	}
+}
@@ -0,0 +1,40 @@
+// Package mediainfo reads the container, codecs and duration of audio and
+// video files without decoding them.
+package mediainfo
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown media format")
+
+	errDurationOutOfRange = errors.New("duration out of range")
+)
+
+// Probe detects the container of the file and reads its metadata.
+// Supported containers are MP4 (ISO BMFF) and Ogg.
+func Probe(data []byte) (domain.MediaInfo, error) {
+	switch {
+	case len(data) >= 8 && bytes.Equal(data[4:8], []byte("ftyp")):
+		return probeMP4(data)
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return probeOgg(data)
+	}
+	return domain.MediaInfo{}, ErrUnknownFormat
+}
+
+// duration returns the duration of units counted at rate units per second.
+// The rate must fit in 32 bits, as headers store it.
+func duration(units, rate uint64) (time.Duration, error) {
+	secs, rem := units/rate, units%rate
+	if secs >= uint64(math.MaxInt64/time.Second) {
+		return 0, errDurationOutOfRange
+	}
+	return time.Duration(secs)*time.Second + time.Duration(rem*uint64(time.Second)/rate), nil
+}
@@ -0,0 +1,205 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func mp4Box(kind string, children ...[]byte) []byte {
+	body := concat(children...)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(b, kind...), body...)
+}
+
+// mvhd returns a version 0 movie header box.
+func mvhd(timescale, duration uint32) []byte {
+	body := make([]byte, 20)
+	binary.BigEndian.PutUint32(body[12:], timescale)
+	binary.BigEndian.PutUint32(body[16:], duration)
+	return mp4Box("mvhd", body)
+}
+
+// mvhd64 returns a version 1 movie header box.
+func mvhd64(timescale uint32, duration uint64) []byte {
+	body := make([]byte, 32)
+	body[0] = 1
+	binary.BigEndian.PutUint32(body[20:], timescale)
+	binary.BigEndian.PutUint64(body[24:], duration)
+	return mp4Box("mvhd", body)
+}
+
+func trak(handler, codec string) []byte {
+	hdlr := append(make([]byte, 8), handler...)
+	stsd := append(make([]byte, 12), codec...)
+	return mp4Box("trak", mp4Box("mdia",
+		mp4Box("hdlr", hdlr),
+		mp4Box("minf", mp4Box("stbl", mp4Box("stsd", stsd))),
+	))
+}
+
+func mp4File(moov ...[]byte) []byte {
+	return concat(mp4Box("ftyp", []byte("isom")), mp4Box("moov", moov...))
+}
+
+func newOggPage(serial uint32, granule uint64, payload []byte) []byte {
+	page := make([]byte, 27, 28+len(payload))
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], serial)
+	page[26] = 1
+	page = append(page, byte(len(payload)))
+	return append(page, payload...)
+}
+
+func opusHead(preSkip uint16) []byte {
+	header := append([]byte("OpusHead"), 1, 2)
+	return binary.LittleEndian.AppendUint16(header, preSkip)
+}
+
+func vorbisHead(rate uint32) []byte {
+	header := append([]byte("\x01vorbis"), 0, 0, 0, 0, 2)
+	return binary.LittleEndian.AppendUint32(header, rate)
+}
+
+func theoraHead(version [3]byte, frn, frd uint32, shift byte) []byte {
+	header := make([]byte, 42)
+	copy(header, "\x80theora")
+	copy(header[7:], version[:])
+	binary.BigEndian.PutUint32(header[22:], frn)
+	binary.BigEndian.PutUint32(header[26:], frd)
+	header[40] = shift >> 3
+	header[41] = shift << 5
+	return header
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    domain.MediaInfo
+		wantErr error
+	}{
+		{
+			name: "mp4 audio",
+			data: mp4File(mvhd(1000, 12500), trak("soun", "mp4a")),
+			want: domain.MediaInfo{MimeType: "audio/mp4", Codecs: []string{"mp4a"}, Duration: 12500 * time.Millisecond},
+		},
+		{
+			name: "mp4 video with 64-bit duration",
+			data: mp4File(mvhd64(90000, 2700000), trak("vide", "avc1"), trak("soun", "mp4a"), trak("text", "tx3g")),
+			want: domain.MediaInfo{
+				MimeType: "video/mp4", Codecs: []string{"avc1", "mp4a"}, Duration: 30 * time.Second, HasVideo: true,
+			},
+		},
+		{
+			name:    "mp4 without moov",
+			data:    mp4Box("ftyp", []byte("isom")),
+			wantErr: errMalformedMP4,
+		},
+		{
+			name:    "mp4 with zero timescale",
+			data:    mp4File(mvhd(0, 100), trak("soun", "mp4a")),
+			wantErr: errMalformedMP4,
+		},
+		{
+			name:    "mp4 without tracks",
+			data:    mp4File(mvhd(1000, 100)),
+			wantErr: errMalformedMP4,
+		},
+		{
+			name:    "mp4 with overflowing duration",
+			data:    mp4File(mvhd64(1, 1<<62), trak("soun", "mp4a")),
+			wantErr: errDurationOutOfRange,
+		},
+		{
+			name:    "truncated mp4",
+			data:    mp4File(mvhd(1000, 100), trak("soun", "mp4a"))[:40],
+			wantErr: errMalformedMP4,
+		},
+		{
+			name: "opus",
+			data: concat(newOggPage(1, 0, opusHead(312)), newOggPage(1, ^uint64(0), nil), newOggPage(1, 48000*5+312, nil)),
+			want: domain.MediaInfo{MimeType: "audio/ogg", Codecs: []string{"opus"}, Duration: 5 * time.Second},
+		},
+		{
+			name: "opus granule before pre-skip",
+			data: concat(newOggPage(1, 0, opusHead(312)), newOggPage(1, 100, nil)),
+			want: domain.MediaInfo{MimeType: "audio/ogg", Codecs: []string{"opus"}},
+		},
+		{
+			name: "vorbis ignores other streams",
+			data: concat(newOggPage(1, 0, vorbisHead(44100)), newOggPage(1, 44100*3/2, nil), newOggPage(2, 44100*60, nil)),
+			want: domain.MediaInfo{MimeType: "audio/ogg", Codecs: []string{"vorbis"}, Duration: 1500 * time.Millisecond},
+		},
+		{
+			name:    "vorbis with zero sample rate",
+			data:    newOggPage(1, 0, vorbisHead(0)),
+			wantErr: errMalformedOgg,
+		},
+		{
+			name: "theora",
+			// The last keyframe is frame 240 and the last page ends 9 frames later.
+			data: concat(newOggPage(1, 0, theoraHead([3]byte{3, 2, 1}, 25, 1, 6)), newOggPage(1, 240<<6|9, nil)),
+			want: domain.MediaInfo{
+				MimeType: "video/ogg", Codecs: []string{"theora"}, Duration: 249 * time.Second / 25, HasVideo: true,
+			},
+		},
+		{
+			name: "theora before 3.2.1 numbers frames from 0",
+			data: concat(newOggPage(1, 0, theoraHead([3]byte{3, 2, 0}, 30000, 1001, 6)), newOggPage(1, 299<<6, nil)),
+			want: domain.MediaInfo{
+				MimeType: "video/ogg", Codecs: []string{"theora"}, Duration: 300 * 1001 * time.Second / 30000, HasVideo: true,
+			},
+		},
+		{
+			name:    "theora with zero frame rate",
+			data:    concat(newOggPage(1, 0, theoraHead([3]byte{3, 2, 1}, 0, 1, 6)), newOggPage(1, 25, nil)),
+			wantErr: errMalformedOgg,
+		},
+		{
+			name:    "theora with overflowing duration",
+			data:    concat(newOggPage(1, 0, theoraHead([3]byte{3, 2, 1}, 1, 1<<31, 0)), newOggPage(1, 1<<40, nil)),
+			wantErr: errDurationOutOfRange,
+		},
+		{
+			name:    "ogg with unknown codec",
+			data:    newOggPage(1, 0, []byte("fishead\x00")),
+			wantErr: errMalformedOgg,
+		},
+		{
+			name:    "truncated ogg",
+			data:    newOggPage(1, 0, opusHead(0))[:30],
+			wantErr: errMalformedOgg,
+		},
+		{
+			name:    "unknown format",
+			data:    []byte("RIFF\x00\x00\x00\x00WAVE"),
+			wantErr: ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Probe(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Probe() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.MimeType != tt.want.MimeType || !slices.Equal(got.Codecs, tt.want.Codecs) ||
+				got.Duration != tt.want.Duration || got.HasVideo != tt.want.HasVideo {
+				t.Errorf("Probe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
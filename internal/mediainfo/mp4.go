@@ -0,0 +1,151 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+var errMalformedMP4 = errors.New("malformed mp4")
+
+type box struct {
+	kind string
+	body []byte
+}
+
+// boxes splits data into consecutive ISO BMFF boxes.
+func boxes(data []byte) ([]box, error) {
+	var res []box
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, errMalformedMP4
+		}
+		size := uint64(binary.BigEndian.Uint32(data))
+		kind := string(data[4:8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, errMalformedMP4
+			}
+			size = binary.BigEndian.Uint64(data[8:])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return nil, errMalformedMP4
+		}
+		res = append(res, box{kind: kind, body: data[header:size]})
+		data = data[size:]
+	}
+	return res, nil
+}
+
+func child(data []byte, kind string) ([]byte, bool) {
+	children, err := boxes(data)
+	if err != nil {
+		return nil, false
+	}
+	for _, b := range children {
+		if b.kind == kind {
+			return b.body, true
+		}
+	}
+	return nil, false
+}
+
+func path(data []byte, kinds ...string) ([]byte, bool) {
+	for _, kind := range kinds {
+		var ok bool
+		data, ok = child(data, kind)
+		if !ok {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+func probeMP4(data []byte) (domain.MediaInfo, error) {
+	top, err := boxes(data)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+	var moov []byte
+	for _, b := range top {
+		if b.kind == "moov" {
+			moov = b.body
+		}
+	}
+	if moov == nil {
+		return domain.MediaInfo{}, fmt.Errorf("%w: no moov box", errMalformedMP4)
+	}
+
+	mvhd, ok := child(moov, "mvhd")
+	if !ok {
+		return domain.MediaInfo{}, fmt.Errorf("%w: no mvhd box", errMalformedMP4)
+	}
+	duration, err := movieDuration(mvhd)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+
+	info := domain.MediaInfo{MimeType: "audio/mp4", Duration: duration}
+	tracks, err := boxes(moov)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+	for _, trak := range tracks {
+		if trak.kind != "trak" {
+			continue
+		}
+		hdlr, ok := path(trak.body, "mdia", "hdlr")
+		if !ok || len(hdlr) < 12 {
+			return domain.MediaInfo{}, fmt.Errorf("%w: no track handler", errMalformedMP4)
+		}
+		handler := string(hdlr[8:12])
+		if handler != "vide" && handler != "soun" {
+			continue
+		}
+		if handler == "vide" {
+			info.HasVideo = true
+			info.MimeType = "video/mp4"
+		}
+		stsd, ok := path(trak.body, "mdia", "minf", "stbl", "stsd")
+		if !ok || len(stsd) < 16 {
+			return domain.MediaInfo{}, fmt.Errorf("%w: no sample description", errMalformedMP4)
+		}
+		info.Codecs = append(info.Codecs, string(stsd[12:16]))
+	}
+	if len(info.Codecs) == 0 {
+		return domain.MediaInfo{}, fmt.Errorf("%w: no audio or video tracks", errMalformedMP4)
+	}
+	return info, nil
+}
+
+func movieDuration(mvhd []byte) (time.Duration, error) {
+	if len(mvhd) < 1 {
+		return 0, errMalformedMP4
+	}
+	var timescale, units uint64
+	if mvhd[0] == 1 {
+		if len(mvhd) < 32 {
+			return 0, errMalformedMP4
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:]))
+		units = binary.BigEndian.Uint64(mvhd[24:])
+	} else {
+		if len(mvhd) < 20 {
+			return 0, errMalformedMP4
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:]))
+		units = uint64(binary.BigEndian.Uint32(mvhd[16:]))
+	}
+	if timescale == 0 {
+		return 0, fmt.Errorf("%w: zero timescale", errMalformedMP4)
+	}
+	return duration(units, timescale)
+}
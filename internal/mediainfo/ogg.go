@@ -0,0 +1,116 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+var errMalformedOgg = errors.New("malformed ogg")
+
+type oggPage struct {
+	granule uint64
+	serial  uint32
+	payload []byte
+}
+
+func oggPages(data []byte) ([]oggPage, error) {
+	var pages []oggPage
+	for len(data) > 0 {
+		if len(data) < 27 || !bytes.HasPrefix(data, []byte("OggS")) {
+			return nil, errMalformedOgg
+		}
+		segments := int(data[26])
+		if len(data) < 27+segments {
+			return nil, errMalformedOgg
+		}
+		size := 0
+		for _, s := range data[27 : 27+segments] {
+			size += int(s)
+		}
+		end := 27 + segments + size
+		if len(data) < end {
+			return nil, errMalformedOgg
+		}
+		pages = append(pages, oggPage{
+			granule: binary.LittleEndian.Uint64(data[6:]),
+			serial:  binary.LittleEndian.Uint32(data[14:]),
+			payload: data[27+segments : end],
+		})
+		data = data[end:]
+	}
+	return pages, nil
+}
+
+func probeOgg(data []byte) (domain.MediaInfo, error) {
+	pages, err := oggPages(data)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+	first := pages[0]
+
+	var granule uint64
+	for _, p := range pages {
+		if p.serial == first.serial && p.granule != ^uint64(0) {
+			granule = p.granule
+		}
+	}
+
+	info := domain.MediaInfo{MimeType: "audio/ogg"}
+	// The duration is counted in units of 1/rate seconds.
+	var units, rate uint64
+	switch header := first.payload; {
+	case bytes.HasPrefix(header, []byte("OpusHead")) && len(header) >= 12:
+		info.Codecs, rate = []string{"opus"}, 48000
+		preSkip := uint64(binary.LittleEndian.Uint16(header[10:]))
+		units = max(granule, preSkip) - preSkip
+	case bytes.HasPrefix(header, []byte("\x01vorbis")) && len(header) >= 16:
+		info.Codecs, units = []string{"vorbis"}, granule
+		rate = uint64(binary.LittleEndian.Uint32(header[12:]))
+	case bytes.HasPrefix(header, []byte("\x80theora")) && len(header) >= 42:
+		info.MimeType, info.Codecs, info.HasVideo = "video/ogg", []string{"theora"}, true
+		units, rate, err = theoraUnits(header, granule)
+		if err != nil {
+			return domain.MediaInfo{}, err
+		}
+	default:
+		return domain.MediaInfo{}, fmt.Errorf("%w: unknown codec", errMalformedOgg)
+	}
+	if rate == 0 {
+		return domain.MediaInfo{}, fmt.Errorf("%w: zero sample rate", errMalformedOgg)
+	}
+	info.Duration, err = duration(units, rate)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+	return info, nil
+}
+
+// theoraUnits converts the granule position of a Theora stream to units of
+// 1/rate seconds. Frames last frd/frn seconds, as read from the
+// identification header, so rate is frn and every frame is frd units.
+func theoraUnits(header []byte, granule uint64) (units, rate uint64, err error) {
+	frn := uint64(binary.BigEndian.Uint32(header[22:]))
+	frd := uint64(binary.BigEndian.Uint32(header[26:]))
+	if granule == 0 {
+		return 0, frn, nil
+	}
+	// The granule position holds the frame number of the last keyframe in
+	// its upper bits and the frames since that keyframe in its lower bits.
+	shift := header[40]&0x03<<3 | header[41]>>5
+	frames := granule>>shift + granule&(1<<shift-1)
+	// Before version 3.2.1 frames are numbered from 0.
+	if major, minor, rev := header[7], header[8], header[9]; major < 3 ||
+		major == 3 && (minor < 2 || minor == 2 && rev < 1) {
+		frames++
+	}
+	hi, units := bits.Mul64(frames, frd)
+	if hi != 0 {
+		return 0, 0, errDurationOutOfRange
+	}
+	return units, frn, nil
+}
@@ -0,0 +1,102 @@
+// Package medialinks turns media object keys stored by the service into
+// signed, expiring URLs handed out to clients.
+package medialinks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// BaseURL is the address media objects are served from.
+	BaseURL string
+	// CDNBaseURL replaces BaseURL in resolved links when set.
+	CDNBaseURL string
+	// Secret signs the links.
+	Secret string
+	// TTL is the minimum time a resolved link stays valid. It must be
+	// positive.
+	TTL time.Duration
+}
+
+type Signer struct {
+	base   string
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(cfg Config) *Signer {
+	base := cfg.BaseURL
+	if cfg.CDNBaseURL != "" {
+		base = cfg.CDNBaseURL
+	}
+	return &Signer{
+		base:   strings.TrimSuffix(base, "/"),
+		secret: []byte(cfg.Secret),
+		ttl:    cfg.TTL,
+		now:    time.Now,
+	}
+}
+
+// Resolve returns the signed URL of the object stored under the key.
+//
+// Keys that are already absolute URLs are returned unchanged. Migration
+// 00013 converted the stored links to keys, so these can only be public
+// links written by instances still running the previous release during
+// the rollout.
+func (s *Signer) Resolve(key string) string {
+	if key == "" || isAbsolute(key) {
+		return key
+	}
+	key = strings.TrimPrefix(key, "/")
+	link := s.base + "/" + escapePath(key)
+	// Expiry is rounded so that repeated reads return the same link
+	// and clients can cache the downloaded object.
+	expires := strconv.FormatInt(s.now().Truncate(s.ttl).Add(2*s.ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return link + "?" + query.Encode()
+}
+
+func (s *Signer) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// escapePath escapes each segment of the key, keeping the slashes
+// between them.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// KeyFromLink extracts the object key from a link returned by the media
+// service. Values that are not absolute URLs are treated as keys.
+func KeyFromLink(link string) string {
+	if !isAbsolute(link) {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
+
+func isAbsolute(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
@@ -0,0 +1,128 @@
+package medialinks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signature(secret, key, expires string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(key + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 7, 30, 0, time.UTC)
+	// Links expire two TTLs after the start of the current TTL window.
+	expires := unix(time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC))
+	tests := []struct {
+		name string
+		cfg  Config
+		key  string
+		want string
+	}{
+		{
+			name: "signed",
+			cfg:  Config{BaseURL: "https://media.example.com/", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "prompts/a.png",
+			want: "https://media.example.com/prompts/a.png?expires=" + expires +
+				"&signature=" + signature("secret", "prompts/a.png", expires),
+		},
+		{
+			name: "leading slash is not signed",
+			cfg:  Config{BaseURL: "https://media.example.com", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "/prompts/a.png",
+			want: "https://media.example.com/prompts/a.png?expires=" + expires +
+				"&signature=" + signature("secret", "prompts/a.png", expires),
+		},
+		{
+			name: "cdn replaces the base url",
+			cfg:  Config{BaseURL: "https://media.example.com", CDNBaseURL: "https://cdn.example.com", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "prompts/a.png",
+			want: "https://cdn.example.com/prompts/a.png?expires=" + expires +
+				"&signature=" + signature("secret", "prompts/a.png", expires),
+		},
+		{
+			name: "segments are escaped and the key is signed as stored",
+			cfg:  Config{BaseURL: "https://media.example.com", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "prompts/my photo?#1.png",
+			want: "https://media.example.com/prompts/my%20photo%3F%231.png?expires=" + expires +
+				"&signature=" + signature("secret", "prompts/my photo?#1.png", expires),
+		},
+		{
+			name: "absolute links are kept",
+			cfg:  Config{BaseURL: "https://media.example.com", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "https://other.example.com/a.png",
+			want: "https://other.example.com/a.png",
+		},
+		{
+			name: "empty key",
+			cfg:  Config{BaseURL: "https://media.example.com", Secret: "secret", TTL: 15 * time.Minute},
+			key:  "",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSigner(tt.cfg)
+			s.now = func() time.Time { return now }
+			got := s.Resolve(tt.key)
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIsStableWithinWindow(t *testing.T) {
+	s := NewSigner(Config{BaseURL: "https://media.example.com", Secret: "secret", TTL: 15 * time.Minute})
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+	first := s.Resolve("a.png")
+	s.now = func() time.Time { return start.Add(14 * time.Minute) }
+	if got := s.Resolve("a.png"); got != first {
+		t.Errorf("Resolve() changed within the window: %q, then %q", first, got)
+	}
+	s.now = func() time.Time { return start.Add(15 * time.Minute) }
+	got := s.Resolve("a.png")
+	if got == first {
+		t.Errorf("Resolve() did not change in the next window")
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The link stays valid for at least the TTL.
+	want := unix(start.Add(45 * time.Minute))
+	if expires := u.Query().Get("expires"); expires != want {
+		t.Errorf("expires = %s, want %s", expires, want)
+	}
+}
+
+func TestKeyFromLink(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{link: "prompts/a.png", want: "prompts/a.png"},
+		{link: "https://media.example.com/prompts/a.png", want: "prompts/a.png"},
+		{link: "https://media.example.com/prompts/a.png?expires=1&signature=x", want: "prompts/a.png"},
+		{link: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			if got := KeyFromLink(tt.link); got != tt.want {
+				t.Errorf("KeyFromLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
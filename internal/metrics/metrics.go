@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "profiles"
+
+var (
+	ProfilesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profiles_created_total",
+		Help:      "Number of created profiles.",
+	})
+	ProfilesUpdated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profiles_updated_total",
+		Help:      "Number of profile updates.",
+	})
+	PromptsAdded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prompts_added_total",
+		Help:      "Number of added prompts by prompt type.",
+	}, []string{"type"})
+	FileUploadSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "file_upload_size_bytes",
+		Help:      "Size of files uploaded to the media service by prompt type.",
+		Buckets:   prometheus.ExponentialBuckets(16*1024, 2, 12),
+	}, []string{"type"})
+	DeduplicatedUploads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deduplicated_uploads_total",
+		Help:      "Number of uploads that reused a file the user already uploaded, by prompt type.",
+	}, []string{"type"})
+	DuplicatePhotosFlagged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_photos_flagged_total",
+		Help:      "Number of uploaded photos matching photos of other users.",
+	})
+	MediaRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "media_request_duration_seconds",
+		Help:      "Latency of calls to the media service by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	MediaBreakerState = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "media_circuit_breaker_state",
+		Help:      "State of the media service circuit breaker: 0 closed, 1 half-open, 2 open.",
+	})
+	MediaBreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "media_circuit_breaker_transitions_total",
+		Help:      "Number of media service circuit breaker state changes.",
+	}, []string{"from", "to"})
+	Recommendations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recommendations_total",
+		Help:      "Number of recommendation requests by result (found or empty).",
+	}, []string{"result"})
+	TxRollbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_rollbacks_total",
+		Help:      "Number of rolled back database transactions by reason.",
+	}, []string{"reason"})
+	TxRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_retries_total",
+		Help:      "Number of retried database transactions by reason.",
+	}, []string{"reason"})
+	DBReads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_routed_reads_total",
+		Help:      "Number of reads routable to replicas by the server that served them: primary or replica.",
+	}, []string{"target"})
+	DBReplicaUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_replica_up",
+		Help:      "Whether the database replica is healthy and receives reads.",
+	}, []string{"replica"})
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Number of cache lookups by entity and result (hit or miss).",
+	}, []string{"entity", "result"})
+	CacheErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_errors_total",
+		Help:      "Number of failed cache operations by operation.",
+	}, []string{"operation"})
+)
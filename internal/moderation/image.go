@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/imaging"
+)
+
+// ImageBlocklist rejects images whose perceptual hash is within maxDistance
+// bits of a blocked hash. Images that cannot be decoded are rejected, as they
+// could not be shown either.
+type ImageBlocklist struct {
+	hashes      []uint64
+	maxDistance int
+	maxPixels   int
+}
+
+func NewImageBlocklist(hashes []string, maxDistance int, maxPixels int) (*ImageBlocklist, error) {
+	b := &ImageBlocklist{maxDistance: maxDistance, maxPixels: maxPixels}
+	for _, h := range hashes {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+		hash, err := strconv.ParseUint(h, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse image hash %q: %w", h, err)
+		}
+		b.hashes = append(b.hashes, hash)
+	}
+	return b, nil
+}
+
+func (b *ImageBlocklist) CheckText(context.Context, string) (domain.ModerationStatus, error) {
+	return domain.ModerationApproved, nil
+}
+
+func (b *ImageBlocklist) CheckImage(_ context.Context, data []byte) (domain.ModerationStatus, error) {
+	if len(b.hashes) == 0 {
+		return domain.ModerationApproved, nil
+	}
+	hash, err := PerceptualHash(data, b.maxPixels)
+	if err != nil {
+		return domain.ModerationRejected, nil
+	}
+	for _, blocked := range b.hashes {
+		if HammingDistance(hash, blocked) <= b.maxDistance {
+			return domain.ModerationRejected, nil
+		}
+	}
+	return domain.ModerationApproved, nil
+}
+
+// PerceptualHash computes the 64-bit difference hash of an encoded image:
+// the image is reduced to 9x8 grayscale cells and every bit tells whether
+// a cell is darker than its right neighbour. Images of more than maxPixels
+// pixels are not decoded.
+func PerceptualHash(data []byte, maxPixels int) (uint64, error) {
+	img, err := imaging.DecodeImage(data, maxPixels)
+	if err != nil {
+		return 0, err
+	}
+	const width, height = 9, 8
+	bounds := img.Bounds()
+	if bounds.Dx() < width || bounds.Dy() < height {
+		return 0, fmt.Errorf("image is smaller than %dx%d", width, height)
+	}
+
+	var cells [height][width]float64
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			var sum float64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			cells[y][x] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if cells[y][x] < cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,131 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+	"github.com/soulmate-dating/profiles/internal/imaging"
+)
+
+// encodeImage encodes a width x height grayscale PNG whose pixels are set by
+// shade.
+func encodeImage(t *testing.T, width, height int, shade func(x, y int) uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: shade(x, y)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func brightening(width int) func(x, y int) uint8 {
+	return func(x, _ int) uint8 { return uint8(x * 255 / (width - 1)) }
+}
+
+func darkening(width int) func(x, y int) uint8 {
+	return func(x, _ int) uint8 { return uint8(255 - x*255/(width-1)) }
+}
+
+func TestPerceptualHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    uint64
+		wantErr bool
+	}{
+		{name: "brightening to the right", data: encodeImage(t, 90, 80, brightening(90)), want: ^uint64(0)},
+		{name: "scaled copy", data: encodeImage(t, 450, 400, brightening(450)), want: ^uint64(0)},
+		{name: "darkening to the right", data: encodeImage(t, 90, 80, darkening(90)), want: 0},
+		{
+			name: "brightening rows on top of darkening rows",
+			data: encodeImage(t, 90, 80, func(x, y int) uint8 {
+				if y < 40 {
+					return brightening(90)(x, y)
+				}
+				return darkening(90)(x, y)
+			}),
+			want: 0xFFFFFFFF00000000,
+		},
+		{name: "uniform", data: encodeImage(t, 90, 80, func(int, int) uint8 { return 128 }), want: 0},
+		{name: "smaller than the grid", data: encodeImage(t, 8, 8, brightening(8)), wantErr: true},
+		{name: "undecodable", data: []byte("not an image"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PerceptualHash(tt.data, 1000*1000)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PerceptualHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PerceptualHash() = %016x, want %016x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerceptualHashTooManyPixels(t *testing.T) {
+	_, err := PerceptualHash(encodeImage(t, 90, 80, brightening(90)), 90*80-1)
+	if !errors.Is(err, imaging.ErrTooManyPixels) {
+		t.Errorf("PerceptualHash() error = %v, want %v", err, imaging.ErrTooManyPixels)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{a: 0, b: 0, want: 0},
+		{a: 0, b: ^uint64(0), want: 64},
+		{a: 0b1011, b: 0b0110, want: 3},
+		{a: 1 << 63, b: 1, want: 2},
+	}
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestImageBlocklist(t *testing.T) {
+	blocklist, err := NewImageBlocklist([]string{"ffffffffffffff00", " "}, 8, 1000*1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want domain.ModerationStatus
+	}{
+		{name: "within the distance of a blocked hash", data: encodeImage(t, 90, 80, brightening(90)), want: domain.ModerationRejected},
+		{name: "far from blocked hashes", data: encodeImage(t, 90, 80, darkening(90)), want: domain.ModerationApproved},
+		{name: "undecodable", data: []byte("not an image"), want: domain.ModerationRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := blocklist.CheckImage(context.Background(), tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckImage() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewImageBlocklist([]string{"not hex"}, 8, 1000*1000); err == nil {
+		t.Error("NewImageBlocklist() accepted an invalid hash")
+	}
+}
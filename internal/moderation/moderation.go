@@ -0,0 +1,80 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+type Mode string
+
+const (
+	// Sync moderates content before it is stored and rejects the request
+	// when the content is not allowed.
+	Sync Mode = "sync"
+	// Async stores content as pending and moderates it in the background.
+	Async Mode = "async"
+)
+
+// Moderator checks user generated content. Implementations return
+// domain.ModerationPending when the content needs a human review.
+type Moderator interface {
+	CheckText(ctx context.Context, text string) (domain.ModerationStatus, error)
+	CheckImage(ctx context.Context, data []byte) (domain.ModerationStatus, error)
+}
+
+type Config struct {
+	BlockedWords     []string
+	BlockedPatterns  []string
+	ImageBlocklist   []string
+	ImageMaxDistance int
+	// MaxImagePixels is the size of the largest image that is decoded.
+	MaxImagePixels int
+}
+
+// New returns the default moderator built from the word list, regex and
+// perceptual hash blocklist filters.
+func New(cfg Config) (Moderator, error) {
+	text, err := NewTextFilter(cfg.BlockedWords, cfg.BlockedPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("text filter: %w", err)
+	}
+	images, err := NewImageBlocklist(cfg.ImageBlocklist, cfg.ImageMaxDistance, cfg.MaxImagePixels)
+	if err != nil {
+		return nil, fmt.Errorf("image blocklist: %w", err)
+	}
+	return Chain{text, images}, nil
+}
+
+// Chain runs every moderator and returns the most restrictive status.
+type Chain []Moderator
+
+func (c Chain) CheckText(ctx context.Context, text string) (domain.ModerationStatus, error) {
+	return c.check(func(m Moderator) (domain.ModerationStatus, error) {
+		return m.CheckText(ctx, text)
+	})
+}
+
+func (c Chain) CheckImage(ctx context.Context, data []byte) (domain.ModerationStatus, error) {
+	return c.check(func(m Moderator) (domain.ModerationStatus, error) {
+		return m.CheckImage(ctx, data)
+	})
+}
+
+func (c Chain) check(f func(m Moderator) (domain.ModerationStatus, error)) (domain.ModerationStatus, error) {
+	result := domain.ModerationApproved
+	for _, m := range c {
+		status, err := f(m)
+		if err != nil {
+			return "", err
+		}
+		switch status {
+		case domain.ModerationRejected:
+			return status, nil
+		case domain.ModerationPending:
+			result = status
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/soulmate-dating/profiles/internal/domain"
+)
+
+// TextFilter rejects texts containing a blocked word or matching a blocked
+// pattern. Words are matched case-insensitively on word boundaries.
+type TextFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func NewTextFilter(words []string, patterns []string) (*TextFilter, error) {
+	f := &TextFilter{}
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) > 0 {
+		f.patterns = append(f.patterns, regexp.MustCompile(`(?i)\b(`+strings.Join(quoted, "|")+`)\b`))
+	}
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern %q: %w", p, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	return f, nil
+}
+
+func (f *TextFilter) CheckText(_ context.Context, text string) (domain.ModerationStatus, error) {
+	for _, re := range f.patterns {
+		if re.MatchString(text) {
+			return domain.ModerationRejected, nil
+		}
+	}
+	return domain.ModerationApproved, nil
+}
+
+func (f *TextFilter) CheckImage(context.Context, []byte) (domain.ModerationStatus, error) {
+	return domain.ModerationApproved, nil
+}
@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"strings"
+	"time"
 )
 
 func (s *ProfileService) CreateProfile(ctx context.Context, request *CreateProfileRequest) (*ProfileResponse, error) {
@@ -18,7 +19,7 @@ func (s *ProfileService) CreateProfile(ctx context.Context, request *CreateProfi
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return ProfileSuccessResponse(profile), nil
+	return ProfileSuccessResponse(profile, s.linksFor("")), nil
 }
 
 func (s *ProfileService) GetProfile(ctx context.Context, request *GetProfileRequest) (*ProfileResponse, error) {
@@ -26,11 +27,15 @@ func (s *ProfileService) GetProfile(ctx context.Context, request *GetProfileRequ
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	profile, err := s.app.GetProfile(ctx, userId)
+	requesterId, err := parseRequesterId(request.GetRequesterId(), userId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	profile, err := s.app.GetProfile(ctx, requesterId, userId)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return ProfileSuccessResponse(profile), nil
+	return ProfileSuccessResponse(profile, s.linksFor(request.GetImageSize())), nil
 }
 
 func (s *ProfileService) UpdateProfile(ctx context.Context, request *UpdateProfileRequest) (*ProfileResponse, error) {
@@ -62,7 +67,7 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, request *UpdateProfi
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return ProfileSuccessResponse(profile), nil
+	return ProfileSuccessResponse(profile, s.linksFor("")), nil
 }
 
 func (s *ProfileService) GetMultipleProfiles(ctx context.Context, request *GetMultipleProfilesRequest) (*MultipleProfilesResponse, error) {
@@ -74,11 +79,35 @@ func (s *ProfileService) GetMultipleProfiles(ctx context.Context, request *GetMu
 		}
 		userIDs[i] = userId
 	}
-	profiles, err := s.app.GetMultipleProfiles(ctx, userIDs)
+	requesterId, err := parseRequesterId(request.GetRequesterId(), uuid.Nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	profiles, err := s.app.GetMultipleProfiles(ctx, requesterId, userIDs)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return GetMultipleProfilesSuccessResponse(profiles), nil
+	return GetMultipleProfilesSuccessResponse(profiles, s.linksFor(request.GetImageSize())), nil
+}
+
+func (s *ProfileService) GetMultipleFullProfiles(ctx context.Context, request *GetMultipleProfilesRequest) (*MultipleFullProfilesResponse, error) {
+	userIDs := make([]uuid.UUID, len(request.GetIds()))
+	for i, id := range request.GetIds() {
+		userId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		userIDs[i] = userId
+	}
+	requesterId, err := parseRequesterId(request.GetRequesterId(), uuid.Nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	profiles, err := s.app.GetMultipleFullProfiles(ctx, requesterId, userIDs)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return MultipleFullProfilesSuccessResponse(profiles, s.linksFor(request.GetImageSize())), nil
 }
 
 func (s *ProfileService) GetRandomProfilePreferredByUser(ctx context.Context, request *GetRandomProfilePreferredByUserRequest) (*FullProfileResponse, error) {
@@ -90,7 +119,7 @@ func (s *ProfileService) GetRandomProfilePreferredByUser(ctx context.Context, re
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return FullProfileSuccessResponse(profile), nil
+	return FullProfileSuccessResponse(profile, s.linksFor(request.GetImageSize())), nil
 }
 
 func (s *ProfileService) GetFullProfile(ctx context.Context, request *GetProfileRequest) (*FullProfileResponse, error) {
@@ -98,11 +127,15 @@ func (s *ProfileService) GetFullProfile(ctx context.Context, request *GetProfile
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	profile, err := s.app.GetFullProfile(ctx, userId)
+	requesterId, err := parseRequesterId(request.GetRequesterId(), userId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	profile, err := s.app.GetFullProfile(ctx, requesterId, userId)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return FullProfileSuccessResponse(profile), nil
+	return FullProfileSuccessResponse(profile, s.linksFor(request.GetImageSize())), nil
 }
 
 func (s *ProfileService) GetPrompts(ctx context.Context, request *GetPromptsRequest) (*PromptsResponse, error) {
@@ -110,11 +143,15 @@ func (s *ProfileService) GetPrompts(ctx context.Context, request *GetPromptsRequ
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	prompts, err := s.app.GetPrompts(ctx, userId)
+	requesterId, err := parseRequesterId(request.GetRequesterId(), userId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	prompts, err := s.app.GetPrompts(ctx, requesterId, userId)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return PromptsSuccessResponse(request.GetUserId(), prompts), nil
+	return PromptsSuccessResponse(request.GetUserId(), prompts, s.linksFor(request.GetImageSize())), nil
 }
 
 func (s *ProfileService) AddPrompts(ctx context.Context, request *AddPromptsRequest) (*PromptsResponse, error) {
@@ -124,12 +161,17 @@ func (s *ProfileService) AddPrompts(ctx context.Context, request *AddPromptsRequ
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
 	for i, p := range request.GetPrompts() {
+		questionId, err := parseOptionalUUID(p.GetQuestionId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		prompts[i] = domain.Prompt{
-			UserId:   userId,
-			Question: p.GetQuestion(),
-			Content:  p.GetContent(),
-			Position: p.GetPosition(),
-			Type:     domain.Text,
+			UserId:     userId,
+			Question:   p.GetQuestion(),
+			Content:    p.GetContent(),
+			Position:   p.GetPosition(),
+			Type:       domain.Text,
+			QuestionId: questionId,
 		}
 	}
 
@@ -137,7 +179,7 @@ func (s *ProfileService) AddPrompts(ctx context.Context, request *AddPromptsRequ
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return PromptsSuccessResponse(request.GetUserId(), prompts), nil
+	return PromptsSuccessResponse(request.GetUserId(), prompts, s.linksFor("")), nil
 }
 
 func (s *ProfileService) UpdatePrompt(ctx context.Context, request *UpdatePromptRequest) (*SinglePromptResponse, error) {
@@ -150,19 +192,24 @@ func (s *ProfileService) UpdatePrompt(ctx context.Context, request *UpdatePrompt
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
+	questionId, err := parseOptionalUUID(promptInfo.GetQuestionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	p := domain.Prompt{
-		ID:       promptId,
-		UserId:   userId,
-		Question: promptInfo.GetQuestion(),
-		Content:  promptInfo.GetContent(),
-		Position: promptInfo.GetPosition(),
-		Type:     domain.ContentType(promptInfo.GetType()),
+		ID:         promptId,
+		UserId:     userId,
+		Question:   promptInfo.GetQuestion(),
+		Content:    promptInfo.GetContent(),
+		Position:   promptInfo.GetPosition(),
+		Type:       domain.ContentType(promptInfo.GetType()),
+		QuestionId: questionId,
 	}
 	prompt, err := s.app.UpdatePrompt(ctx, p)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return SinglePromptSuccessResponse(prompt), nil
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
 }
 
 func (s *ProfileService) UpdatePromptsPositions(ctx context.Context, request *UpdatePromptsPositionsRequest) (*PromptsResponse, error) {
@@ -187,7 +234,7 @@ func (s *ProfileService) UpdatePromptsPositions(ctx context.Context, request *Up
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return PromptsSuccessResponse(request.GetUserId(), prompts), nil
+	return PromptsSuccessResponse(request.GetUserId(), prompts, s.linksFor("")), nil
 }
 
 func (s *ProfileService) AddFilePrompt(ctx context.Context, request *AddFilePromptRequest) (*SinglePromptResponse, error) {
@@ -195,18 +242,23 @@ func (s *ProfileService) AddFilePrompt(ctx context.Context, request *AddFileProm
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
+	questionId, err := parseOptionalUUID(request.GetQuestionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	filePrompt := domain.FilePrompt{
-		UserId:   userId,
-		Question: request.GetQuestion(),
-		Content:  request.GetContent(),
-		Position: request.GetPosition(),
-		Type:     domain.Image,
+		UserId:     userId,
+		Question:   request.GetQuestion(),
+		Content:    request.GetContent(),
+		Position:   request.GetPosition(),
+		Type:       domain.Image,
+		QuestionId: questionId,
 	}
 	prompt, err := s.app.AddFilePrompt(ctx, filePrompt)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return SinglePromptSuccessResponse(prompt), nil
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
 }
 
 func (s *ProfileService) UpdateFilePrompt(ctx context.Context, request *UpdateFilePromptRequest) (*SinglePromptResponse, error) {
@@ -218,19 +270,127 @@ func (s *ProfileService) UpdateFilePrompt(ctx context.Context, request *UpdateFi
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
+	questionId, err := parseOptionalUUID(request.GetQuestionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	filePrompt := domain.FilePrompt{
-		ID:       promptId,
-		UserId:   userId,
-		Question: request.GetQuestion(),
-		Content:  request.GetContent(),
-		Position: request.GetPosition(),
-		Type:     domain.Image,
+		ID:         promptId,
+		UserId:     userId,
+		Question:   request.GetQuestion(),
+		Content:    request.GetContent(),
+		Position:   request.GetPosition(),
+		Type:       domain.Image,
+		QuestionId: questionId,
 	}
 	prompt, err := s.app.UpdateFilePrompt(ctx, filePrompt)
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return SinglePromptSuccessResponse(prompt), nil
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) AddMediaPrompt(ctx context.Context, request *AddMediaPromptRequest) (*SinglePromptResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	questionId, err := parseOptionalUUID(request.GetQuestionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	filePrompt := domain.FilePrompt{
+		UserId:     userId,
+		Question:   request.GetQuestion(),
+		Content:    request.GetContent(),
+		Thumbnail:  request.GetThumbnail(),
+		Position:   request.GetPosition(),
+		Type:       domain.ContentType(strings.ToLower(request.GetType())),
+		QuestionId: questionId,
+	}
+	if !filePrompt.Type.IsMedia() {
+		return nil, status.Error(codes.InvalidArgument, "type must be audio or video")
+	}
+	prompt, err := s.app.AddFilePrompt(ctx, filePrompt)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) UpdateMediaPrompt(ctx context.Context, request *UpdateMediaPromptRequest) (*SinglePromptResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	promptId, err := uuid.Parse(request.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	questionId, err := parseOptionalUUID(request.GetQuestionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	filePrompt := domain.FilePrompt{
+		ID:         promptId,
+		UserId:     userId,
+		Question:   request.GetQuestion(),
+		Content:    request.GetContent(),
+		Thumbnail:  request.GetThumbnail(),
+		Position:   request.GetPosition(),
+		Type:       domain.ContentType(strings.ToLower(request.GetType())),
+		QuestionId: questionId,
+	}
+	if !filePrompt.Type.IsMedia() {
+		return nil, status.Error(codes.InvalidArgument, "type must be audio or video")
+	}
+	prompt, err := s.app.UpdateFilePrompt(ctx, filePrompt)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) UploadFilePrompt(stream ProfileService_UploadFilePromptServer) error {
+	header, content, err := receiveUpload(stream, s.maxUploadSize)
+	if err != nil {
+		return err
+	}
+	userId, err := uuid.Parse(header.GetUserId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	questionId, err := parseOptionalUUID(header.GetQuestionId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	filePrompt := domain.FilePrompt{
+		UserId:     userId,
+		Question:   header.GetQuestion(),
+		Content:    content,
+		Thumbnail:  header.GetThumbnail(),
+		Position:   header.GetPosition(),
+		Type:       domain.ContentType(strings.ToLower(header.GetType())),
+		QuestionId: questionId,
+	}
+	if filePrompt.Type != domain.Image && !filePrompt.Type.IsMedia() {
+		return status.Error(codes.InvalidArgument, "type must be image, audio or video")
+	}
+
+	var prompt *domain.Prompt
+	if header.GetId() == "" {
+		prompt, err = s.app.AddFilePrompt(stream.Context(), filePrompt)
+	} else {
+		filePrompt.ID, err = uuid.Parse(header.GetId())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		prompt, err = s.app.UpdateFilePrompt(stream.Context(), filePrompt)
+	}
+	if err != nil {
+		return status.Error(GetErrorCode(err), err.Error())
+	}
+	return stream.SendAndClose(SinglePromptSuccessResponse(prompt, s.linksFor("")))
 }
 
 func (s *ProfileService) DeletePrompt(ctx context.Context, request *DeletePromptRequest) (*SinglePromptResponse, error) {
@@ -246,5 +406,343 @@ func (s *ProfileService) DeletePrompt(ctx context.Context, request *DeletePrompt
 	if err != nil {
 		return nil, status.Error(GetErrorCode(err), err.Error())
 	}
-	return SinglePromptSuccessResponse(prompt), nil
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) SearchPrompts(ctx context.Context, request *SearchPromptsRequest) (*SearchPromptsResponse, error) {
+	requesterId, err := parseRequesterId(request.GetRequesterId(), uuid.Nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	result, err := s.app.SearchPrompts(ctx,
+		requesterId, request.GetQuery(), int(request.GetLimit()), int(request.GetOffset()),
+	)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SearchPromptsSuccessResponse(result, s.linksFor(request.GetImageSize())), nil
+}
+
+func (s *ProfileService) BlockUser(ctx context.Context, request *BlockUserRequest) (*BlockResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	blockedId, err := uuid.Parse(request.GetBlockedUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	block, err := s.app.BlockUser(ctx, userId, blockedId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return BlockSuccessResponse(block), nil
+}
+
+func (s *ProfileService) UnblockUser(ctx context.Context, request *BlockUserRequest) (*BlockResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	blockedId, err := uuid.Parse(request.GetBlockedUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	block, err := s.app.UnblockUser(ctx, userId, blockedId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return BlockSuccessResponse(block), nil
+}
+
+func (s *ProfileService) ListBlockedUsers(ctx context.Context, request *ListBlockedUsersRequest) (*BlockedUsersResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	blocks, err := s.app.ListBlockedUsers(ctx, userId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return BlockedUsersSuccessResponse(request.GetUserId(), blocks), nil
+}
+
+func (s *ProfileService) SetVisibility(ctx context.Context, request *SetVisibilityRequest) (*ProfileResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	visibility := domain.Visibility(strings.ToLower(request.GetVisibility()))
+	profile, err := s.app.SetVisibility(ctx, userId, visibility)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ProfileSuccessResponse(profile, s.linksFor("")), nil
+}
+
+func (s *ProfileService) AddGalleryImage(ctx context.Context, request *AddGalleryImageRequest) (*SinglePromptResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	promptId, err := uuid.Parse(request.GetPromptId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	prompt, err := s.app.AddGalleryImage(ctx, domain.GalleryUpload{
+		UserId:   userId,
+		PromptId: promptId,
+		Content:  request.GetContent(),
+		Caption:  request.GetCaption(),
+		Position: request.GetPosition(),
+	})
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) RemoveGalleryImage(ctx context.Context, request *RemoveGalleryImageRequest) (*SinglePromptResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	promptId, err := uuid.Parse(request.GetPromptId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	imageId, err := uuid.Parse(request.GetImageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	prompt, err := s.app.RemoveGalleryImage(ctx, userId, promptId, imageId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) ReorderGalleryImages(ctx context.Context, request *ReorderGalleryImagesRequest) (*SinglePromptResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	promptId, err := uuid.Parse(request.GetPromptId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	imageIds := make([]uuid.UUID, len(request.GetImageIds()))
+	for i, id := range request.GetImageIds() {
+		imageIds[i], err = uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	prompt, err := s.app.ReorderGalleryImages(ctx, userId, promptId, imageIds)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return SinglePromptSuccessResponse(prompt, s.linksFor("")), nil
+}
+
+func (s *ProfileService) SetMainPicture(ctx context.Context, request *SetMainPictureRequest) (*ProfileResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	promptId, err := uuid.Parse(request.GetPromptId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	profile, err := s.app.SetMainPicture(ctx, userId, promptId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ProfileSuccessResponse(profile, s.linksFor("")), nil
+}
+
+func (s *ProfileService) AllowViewer(ctx context.Context, request *ViewerRequest) (*AllowedViewerResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	viewerId, err := uuid.Parse(request.GetViewerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	viewer, err := s.app.AllowViewer(ctx, userId, viewerId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return AllowedViewerSuccessResponse(viewer), nil
+}
+
+func (s *ProfileService) DisallowViewer(ctx context.Context, request *ViewerRequest) (*AllowedViewerResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	viewerId, err := uuid.Parse(request.GetViewerId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	viewer, err := s.app.DisallowViewer(ctx, userId, viewerId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return AllowedViewerSuccessResponse(viewer), nil
+}
+
+func (s *ProfileService) ListAllowedViewers(ctx context.Context, request *ListAllowedViewersRequest) (*AllowedViewersResponse, error) {
+	userId, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	viewers, err := s.app.ListAllowedViewers(ctx, userId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return AllowedViewersSuccessResponse(request.GetUserId(), viewers), nil
+}
+
+func (s *ProfileService) ReportProfile(ctx context.Context, request *ReportProfileRequest) (*ReportResponse, error) {
+	report, err := mapReportProfileRequest(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	report, err = s.app.ReportProfile(ctx, *report)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ReportSuccessResponse(report), nil
+}
+
+func (s *ProfileService) ListReports(ctx context.Context, request *ListReportsRequest) (*ReportsResponse, error) {
+	reports, err := s.app.ListReports(ctx,
+		reportStatuses[request.GetStatus()], int(request.GetLimit()), int(request.GetOffset()),
+	)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ReportsSuccessResponse(reports), nil
+}
+
+func (s *ProfileService) ListProfiles(ctx context.Context, request *ListProfilesRequest) (*ProfilesPageResponse, error) {
+	filter := domain.ProfileFilter{
+		Sex:       request.GetSex(),
+		MinAge:    int(request.GetMinAge()),
+		MaxAge:    int(request.GetMaxAge()),
+		Location:  request.GetLocation(),
+		Intention: request.GetIntention(),
+		HasPhotos: request.HasPhotos,
+	}
+	var err error
+	if request.GetCreatedAfter() != "" {
+		filter.CreatedAfter, err = time.Parse(time.RFC3339, request.GetCreatedAfter())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if request.GetCreatedBefore() != "" {
+		filter.CreatedBefore, err = time.Parse(time.RFC3339, request.GetCreatedBefore())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	cursor, err := domain.ParseProfileCursor(request.GetCursor())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := s.app.ListProfiles(ctx, filter, cursor, int(request.GetPageSize()))
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ProfilesPageSuccessResponse(page, s.linksFor("")), nil
+}
+
+func (s *ProfileService) ClaimReport(ctx context.Context, request *ClaimReportRequest) (*ReportResponse, error) {
+	reportId, err := uuid.Parse(request.GetReportId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	moderatorId, err := uuid.Parse(request.GetModeratorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	report, err := s.app.ClaimReport(ctx, reportId, moderatorId)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ReportSuccessResponse(report), nil
+}
+
+func (s *ProfileService) ResolveReport(ctx context.Context, request *ResolveReportRequest) (*ReportResponse, error) {
+	reportId, err := uuid.Parse(request.GetReportId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	moderatorId, err := uuid.Parse(request.GetModeratorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	report, err := s.app.ResolveReport(ctx, domain.Resolution{
+		ReportId:    reportId,
+		ModeratorId: moderatorId,
+		Action:      moderationActions[request.GetAction()],
+		Note:        request.GetNote(),
+	})
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return ReportSuccessResponse(report), nil
+}
+
+func (s *ProfileService) ListPromptQuestions(ctx context.Context, request *ListPromptQuestionsRequest) (*PromptQuestionsResponse, error) {
+	questions, err := s.app.ListPromptQuestions(ctx, request.GetCategory(), request.GetIncludeInactive())
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return PromptQuestionsSuccessResponse(questions, request.GetLocale()), nil
+}
+
+func (s *ProfileService) CreatePromptQuestion(ctx context.Context, request *PromptQuestionRequest) (*PromptQuestionResponse, error) {
+	question, err := s.app.CreatePromptQuestion(ctx, mapPromptQuestionRequest(request))
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return PromptQuestionSuccessResponse(question), nil
+}
+
+func (s *ProfileService) UpdatePromptQuestion(ctx context.Context, request *PromptQuestionRequest) (*PromptQuestionResponse, error) {
+	id, err := uuid.Parse(request.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	q := mapPromptQuestionRequest(request)
+	q.ID = id
+	question, err := s.app.UpdatePromptQuestion(ctx, q)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return PromptQuestionSuccessResponse(question), nil
+}
+
+func (s *ProfileService) DeletePromptQuestion(ctx context.Context, request *DeletePromptQuestionRequest) (*PromptQuestionResponse, error) {
+	id, err := uuid.Parse(request.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	question, err := s.app.DeletePromptQuestion(ctx, id)
+	if err != nil {
+		return nil, status.Error(GetErrorCode(err), err.Error())
+	}
+	return PromptQuestionSuccessResponse(question), nil
+}
+
+// parseRequesterId parses the optional requester ID, falling back to the given default when it is empty.
+func parseRequesterId(requesterId string, fallback uuid.UUID) (uuid.UUID, error) {
+	if requesterId == "" {
+		return fallback, nil
+	}
+	return uuid.Parse(requesterId)
 }
@@ -3,15 +3,77 @@ package grpc
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/soulmate-dating/profiles/internal/domain"
 )
 
-func ProfileSuccessResponse(p *domain.Profile) *ProfileResponse {
+// LinkResolver turns media object keys into links handed out to clients.
+type LinkResolver interface {
+	Resolve(key string) string
+}
+
+// mediaLinks resolves the media keys of a response, preferring the
+// renditions of the image size requested by the client.
+type mediaLinks struct {
+	resolver LinkResolver
+	size     string
+}
+
+func (l mediaLinks) Resolve(key string) string {
+	return l.resolver.Resolve(key)
+}
+
+func (l mediaLinks) image(key string, renditions domain.Renditions) string {
+	return l.Resolve(renditions.Pick(l.size, key))
+}
+
+func (l mediaLinks) renditions(renditions domain.Renditions) map[string]string {
+	if len(renditions) == 0 {
+		return nil
+	}
+	return lo.MapValues(renditions, func(key string, _ string) string {
+		return l.Resolve(key)
+	})
+}
+
+func ProfileSuccessResponse(p *domain.Profile, links mediaLinks) *ProfileResponse {
+	return mapProfile(*p, links)
+}
+
+func GetMultipleProfilesSuccessResponse(profiles []domain.Profile, links mediaLinks) *MultipleProfilesResponse {
+	return &MultipleProfilesResponse{Profiles: mapProfiles(profiles, links)}
+}
+
+func ProfilesPageSuccessResponse(page *domain.ProfilePage, links mediaLinks) *ProfilesPageResponse {
+	res := &ProfilesPageResponse{Profiles: make([]*AdminProfile, len(page.Profiles))}
+	for i, p := range page.Profiles {
+		res.Profiles[i] = &AdminProfile{Profile: mapProfile(p, links), Suspended: p.Suspended}
+		if !p.CreatedAt.IsZero() {
+			res.Profiles[i].CreatedAt = p.CreatedAt.Format(time.RFC3339)
+		}
+	}
+	if page.Next != nil {
+		res.NextCursor = page.Next.Encode()
+	}
+	return res
+}
+
+func mapProfiles(profiles []domain.Profile, links mediaLinks) []*ProfileResponse {
+	res := make([]*ProfileResponse, len(profiles))
+	for i, p := range profiles {
+		res[i] = mapProfile(p, links)
+	}
+	return res
+}
+
+func mapProfile(p domain.Profile, links mediaLinks) *ProfileResponse {
 	return &ProfileResponse{
 		Id: p.UserId.String(),
 		PersonalInfo: &PersonalInfo{
@@ -27,77 +89,61 @@ func ProfileSuccessResponse(p *domain.Profile) *ProfileResponse {
 			Location:         p.Location,
 			DrinksAlcohol:    p.DrinksAlcohol,
 			Smokes:           p.Smokes,
-			ProfilePicLink:   p.MainPicLink,
+			ProfilePicLink:   links.image(p.MainPicLink, p.MainPicRenditions),
 		},
+		Visibility: string(p.Visibility),
 	}
 }
 
-func GetMultipleProfilesSuccessResponse(profiles []domain.Profile) *MultipleProfilesResponse {
-	res := make([]*ProfileResponse, len(profiles))
-	for i, p := range profiles {
-		res[i] = &ProfileResponse{
-			Id: p.UserId.String(),
-			PersonalInfo: &PersonalInfo{
-				FirstName:        p.FirstName,
-				LastName:         p.LastName,
-				BirthDate:        p.BirthDate.Format(domain.DateLayout),
-				Sex:              p.Sex,
-				PreferredPartner: p.PreferredPartner,
-				Intention:        p.Intention,
-				Height:           p.Height,
-				HasChildren:      p.HasChildren,
-				FamilyPlans:      p.FamilyPlans,
-				Location:         p.Location,
-				DrinksAlcohol:    p.DrinksAlcohol,
-				Smokes:           p.Smokes,
-				ProfilePicLink:   p.MainPicLink,
-			},
-		}
+func PromptsSuccessResponse(userId string, prompts []domain.Prompt, links mediaLinks) *PromptsResponse {
+	res := make([]*Prompt, len(prompts))
+	for i, p := range prompts {
+		res[i] = mapPrompt(p, links)
 	}
-	return &MultipleProfilesResponse{Profiles: res}
+	return &PromptsResponse{UserId: userId, Prompts: res}
 }
 
-func PromptsSuccessResponse(userId string, prompts []domain.Prompt) *PromptsResponse {
-	res := make([]*Prompt, len(prompts))
-	for i, p := range prompts {
-		res[i] = &Prompt{
-			Id:       p.ID.String(),
-			Question: p.Question,
-			Content:  p.Content,
-			Position: p.Position,
-			Type:     string(p.Type),
+func SearchPromptsSuccessResponse(result *domain.PromptSearchResult, links mediaLinks) *SearchPromptsResponse {
+	matches := make([]*PromptMatch, len(result.Matches))
+	for i, m := range result.Matches {
+		matches[i] = &PromptMatch{
+			UserId:  m.UserId.String(),
+			Prompt:  mapPrompt(m.Prompt, links),
+			Snippet: m.Snippet,
 		}
 	}
-	return &PromptsResponse{UserId: userId, Prompts: res}
+	return &SearchPromptsResponse{Matches: matches, Profiles: mapProfiles(result.Profiles, links)}
 }
 
-func SinglePromptSuccessResponse(p *domain.Prompt) *SinglePromptResponse {
+func SinglePromptSuccessResponse(p *domain.Prompt, links mediaLinks) *SinglePromptResponse {
 	return &SinglePromptResponse{
 		UserId: p.UserId.String(),
-		Prompt: &Prompt{
-			Id:       p.ID.String(),
-			Question: p.Question,
-			Content:  p.Content,
-			Position: p.Position,
-			Type:     string(p.Type),
-		},
+		Prompt: mapPrompt(*p, links),
 	}
 }
 
-func FullProfileSuccessResponse(fp *domain.FullProfile) *FullProfileResponse {
+func MultipleFullProfilesSuccessResponse(profiles *domain.FullProfiles, links mediaLinks) *MultipleFullProfilesResponse {
+	res := &MultipleFullProfilesResponse{
+		Profiles:   make([]*FullProfileResponse, len(profiles.Profiles)),
+		MissingIds: make([]string, len(profiles.MissingIds)),
+	}
+	for i := range profiles.Profiles {
+		res.Profiles[i] = FullProfileSuccessResponse(&profiles.Profiles[i], links)
+	}
+	for i, id := range profiles.MissingIds {
+		res.MissingIds[i] = id.String()
+	}
+	return res
+}
+
+func FullProfileSuccessResponse(fp *domain.FullProfile, links mediaLinks) *FullProfileResponse {
 	prompts := fp.Prompts
 	res := make([]*Prompt, len(prompts))
 	for i, p := range prompts {
-		res[i] = &Prompt{
-			Id:       p.ID.String(),
-			Question: p.Question,
-			Content:  p.Content,
-			Position: p.Position,
-			Type:     string(p.Type),
-		}
+		res[i] = mapPrompt(p, links)
 	}
 	profile := fp.Profile
-	return &FullProfileResponse{
+	response := &FullProfileResponse{
 		UserId: profile.UserId.String(),
 		PersonalInfo: &PersonalInfo{
 			FirstName:        profile.FirstName,
@@ -112,12 +158,237 @@ func FullProfileSuccessResponse(fp *domain.FullProfile) *FullProfileResponse {
 			Location:         profile.Location,
 			DrinksAlcohol:    profile.DrinksAlcohol,
 			Smokes:           profile.Smokes,
-			ProfilePicLink:   profile.MainPicLink,
+			ProfilePicLink:   links.image(profile.MainPicLink, profile.MainPicRenditions),
 		},
-		Prompts: res,
+		Prompts:    res,
+		Visibility: string(profile.Visibility),
+	}
+	if fp.Completeness != nil {
+		response.Completeness = mapCompleteness(*fp.Completeness)
+	}
+	return response
+}
+
+func mapCompleteness(c domain.Completeness) *Completeness {
+	missing := make([]string, len(c.Missing))
+	for i, item := range c.Missing {
+		missing[i] = string(item)
+	}
+	return &Completeness{Score: int32(c.Score), Missing: missing}
+}
+
+func BlockSuccessResponse(b *domain.Block) *BlockResponse {
+	return &BlockResponse{Block: mapBlock(*b)}
+}
+
+func BlockedUsersSuccessResponse(userId string, blocks []domain.Block) *BlockedUsersResponse {
+	res := make([]*Block, len(blocks))
+	for i, b := range blocks {
+		res[i] = mapBlock(b)
+	}
+	return &BlockedUsersResponse{UserId: userId, Blocks: res}
+}
+
+func mapBlock(b domain.Block) *Block {
+	return &Block{
+		UserId:        b.BlockerId.String(),
+		BlockedUserId: b.BlockedId.String(),
+		CreatedAt:     b.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func AllowedViewerSuccessResponse(v *domain.AllowedViewer) *AllowedViewerResponse {
+	return &AllowedViewerResponse{Viewer: mapAllowedViewer(*v)}
+}
+
+func AllowedViewersSuccessResponse(userId string, viewers []domain.AllowedViewer) *AllowedViewersResponse {
+	res := make([]*AllowedViewer, len(viewers))
+	for i, v := range viewers {
+		res[i] = mapAllowedViewer(v)
+	}
+	return &AllowedViewersResponse{UserId: userId, Viewers: res}
+}
+
+func mapAllowedViewer(v domain.AllowedViewer) *AllowedViewer {
+	return &AllowedViewer{
+		UserId:    v.UserId.String(),
+		ViewerId:  v.ViewerId.String(),
+		CreatedAt: v.CreatedAt.Format(time.RFC3339),
 	}
 }
 
+var (
+	reportReasons = map[ReportReason]domain.ReportReason{
+		ReportReason_REPORT_REASON_FAKE_PROFILE:          domain.ReasonFakeProfile,
+		ReportReason_REPORT_REASON_INAPPROPRIATE_CONTENT: domain.ReasonInappropriateContent,
+		ReportReason_REPORT_REASON_HARASSMENT:            domain.ReasonHarassment,
+		ReportReason_REPORT_REASON_DUPLICATE_PHOTO:       domain.ReasonDuplicatePhoto,
+		ReportReason_REPORT_REASON_SPAM:                  domain.ReasonSpam,
+		ReportReason_REPORT_REASON_UNDERAGE:              domain.ReasonUnderage,
+		ReportReason_REPORT_REASON_OTHER:                 domain.ReasonOther,
+	}
+	reportStatuses = map[ReportStatus]domain.ReportStatus{
+		ReportStatus_REPORT_STATUS_OPEN:     domain.ReportOpen,
+		ReportStatus_REPORT_STATUS_CLAIMED:  domain.ReportClaimed,
+		ReportStatus_REPORT_STATUS_RESOLVED: domain.ReportResolved,
+	}
+	moderationActions = map[ModerationAction]domain.ModerationAction{
+		ModerationAction_MODERATION_ACTION_DISMISS:         domain.ActionDismiss,
+		ModerationAction_MODERATION_ACTION_REMOVE_PROMPT:   domain.ActionRemovePrompt,
+		ModerationAction_MODERATION_ACTION_SUSPEND_PROFILE: domain.ActionSuspendProfile,
+	}
+
+	reportReasonsToProto     = lo.Invert(reportReasons)
+	reportStatusesToProto    = lo.Invert(reportStatuses)
+	moderationActionsToProto = lo.Invert(moderationActions)
+)
+
+func ReportSuccessResponse(r *domain.Report) *ReportResponse {
+	return &ReportResponse{Report: mapReport(*r)}
+}
+
+func ReportsSuccessResponse(reports []domain.Report) *ReportsResponse {
+	res := make([]*Report, len(reports))
+	for i, r := range reports {
+		res[i] = mapReport(r)
+	}
+	return &ReportsResponse{Reports: res}
+}
+
+func mapReport(r domain.Report) *Report {
+	res := &Report{
+		Id:             r.ID.String(),
+		ReporterId:     optionalUUID(r.ReporterId),
+		ReportedUserId: r.ReportedId.String(),
+		Reason:         reportReasonsToProto[r.Reason],
+		Comment:        r.Comment,
+		Status:         reportStatusesToProto[r.Status],
+		ResolutionNote: r.ResolutionNote,
+		CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+	}
+	res.PromptId = optionalUUID(r.PromptId)
+	res.ModeratorId = optionalUUID(r.ModeratorId)
+	if r.Action != nil {
+		res.Action = moderationActionsToProto[*r.Action]
+	}
+	if r.ClaimedAt != nil {
+		res.ClaimedAt = r.ClaimedAt.Format(time.RFC3339)
+	}
+	if r.ResolvedAt != nil {
+		res.ResolvedAt = r.ResolvedAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+func mapReportProfileRequest(request *ReportProfileRequest) (*domain.Report, error) {
+	reporterId, err := uuid.Parse(request.GetReporterId())
+	if err != nil {
+		return nil, err
+	}
+	reportedId, err := uuid.Parse(request.GetReportedUserId())
+	if err != nil {
+		return nil, err
+	}
+	report := &domain.Report{
+		ReporterId: &reporterId,
+		ReportedId: reportedId,
+		Reason:     reportReasons[request.GetReason()],
+		Comment:    request.GetComment(),
+	}
+	report.PromptId, err = parseOptionalUUID(request.GetPromptId())
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func PromptQuestionSuccessResponse(q *domain.PromptQuestion) *PromptQuestionResponse {
+	return &PromptQuestionResponse{Question: mapPromptQuestion(*q, domain.DefaultLocale)}
+}
+
+func PromptQuestionsSuccessResponse(questions []domain.PromptQuestion, locale string) *PromptQuestionsResponse {
+	res := make([]*PromptQuestion, len(questions))
+	for i, q := range questions {
+		res[i] = mapPromptQuestion(q, locale)
+	}
+	return &PromptQuestionsResponse{Questions: res}
+}
+
+func mapPromptQuestion(q domain.PromptQuestion, locale string) *PromptQuestion {
+	return &PromptQuestion{
+		Id:       q.ID.String(),
+		Text:     q.Text(locale),
+		Texts:    q.Texts,
+		Category: q.Category,
+		ContentTypes: lo.Map(q.ContentTypes, func(t domain.ContentType, _ int) string {
+			return string(t)
+		}),
+		Active: q.Active,
+	}
+}
+
+func mapPromptQuestionRequest(request *PromptQuestionRequest) domain.PromptQuestion {
+	return domain.PromptQuestion{
+		Texts:    request.GetTexts(),
+		Category: request.GetCategory(),
+		ContentTypes: lo.Map(request.GetContentTypes(), func(t string, _ int) domain.ContentType {
+			return domain.ContentType(strings.ToLower(t))
+		}),
+		Active: request.GetActive(),
+	}
+}
+
+func mapPrompt(p domain.Prompt, links mediaLinks) *Prompt {
+	content := p.Content
+	if p.Type.HasFile() {
+		content = links.image(content, p.Renditions)
+	}
+	return &Prompt{
+		Id:               p.ID.String(),
+		Question:         p.Question,
+		Content:          content,
+		Position:         p.Position,
+		Type:             string(p.Type),
+		QuestionId:       optionalUUID(p.QuestionId),
+		ModerationStatus: string(p.ModerationStatus),
+		DurationMs:       p.DurationMs,
+		ThumbnailLink:    links.Resolve(p.ThumbnailLink),
+		Images: lo.Map(p.Images, func(i domain.GalleryImage, _ int) *GalleryImage {
+			return mapGalleryImage(i, links)
+		}),
+		Renditions: links.renditions(p.Renditions),
+	}
+}
+
+func mapGalleryImage(i domain.GalleryImage, links mediaLinks) *GalleryImage {
+	return &GalleryImage{
+		Id:               i.ID.String(),
+		Link:             links.image(i.Link, i.Renditions),
+		Caption:          i.Caption,
+		Position:         i.Position,
+		ModerationStatus: string(i.ModerationStatus),
+		Renditions:       links.renditions(i.Renditions),
+	}
+}
+
+func optionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func parseOptionalUUID(s string) (*uuid.UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
 func mapCreateProfileRequest(request *CreateProfileRequest) (*domain.Profile, error) {
 	info := request.GetPersonalInfo()
 	userId, err := uuid.Parse(request.GetId())
@@ -147,18 +418,36 @@ func mapCreateProfileRequest(request *CreateProfileRequest) (*domain.Profile, er
 
 func GetErrorCode(err error) codes.Code {
 	switch {
-	case errors.As(err, &validator.ValidationErrors{}):
+	case errors.As(err, &validator.ValidationErrors{}) || errors.As(err, new(*domain.ValidationError)):
 		return codes.InvalidArgument
 	case errors.Is(err, domain.ErrNotFound):
 		return codes.NotFound
 	case errors.Is(err, domain.ErrNotUnique) || errors.Is(err, domain.ErrIDAlreadyExists):
 		return codes.AlreadyExists
-	case errors.Is(err, domain.ErrAddPromptsOnEmptyProfile):
+	case errors.Is(err, domain.ErrAddPromptsOnEmptyProfile) || errors.Is(err, domain.ErrStillReferenced):
 		return codes.FailedPrecondition
 	case errors.Is(err, domain.ErrForbidden):
 		return codes.PermissionDenied
-	case errors.Is(err, domain.ErrCannotDeleteProfilePic):
+	case errors.Is(err, domain.ErrCannotBlockSelf) || errors.Is(err, domain.ErrCannotReportSelf) ||
+		errors.Is(err, domain.ErrInvalidCursor) || errors.Is(err, domain.ErrEmptySearchQuery) ||
+		errors.Is(err, domain.ErrBatchTooLarge):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrReportNotOpen) || errors.Is(err, domain.ErrReportNotClaimed) ||
+		errors.Is(err, domain.ErrReportWithoutPrompt):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrQuestionInactive) || errors.Is(err, domain.ErrQuestionInUse) ||
+		errors.Is(err, domain.ErrQuestionTextTaken):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrContentTypeNotAllowed) || errors.Is(err, domain.ErrInvalidPromptLayout) ||
+		errors.Is(err, domain.ErrContentRejected) || errors.Is(err, domain.ErrUnsupportedMedia) ||
+		errors.Is(err, domain.ErrMediaTooLong):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrTooManyPrompts) || errors.Is(err, domain.ErrNotImagePrompt) ||
+		errors.Is(err, domain.ErrImageNotApproved) || errors.Is(err, domain.ErrPromptTypeChanged) || errors.Is(err, domain.ErrNotGalleryPrompt) ||
+		errors.Is(err, domain.ErrTooManyGalleryImages) || errors.Is(err, domain.ErrFileRequired):
 		return codes.FailedPrecondition
+	case status.Code(err) == codes.Unavailable:
+		return codes.Unavailable
 	}
 	return codes.Internal
 }
@@ -26,15 +26,47 @@ type ProfileServiceClient interface {
 	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*ProfileResponse, error)
 	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*ProfileResponse, error)
 	GetMultipleProfiles(ctx context.Context, in *GetMultipleProfilesRequest, opts ...grpc.CallOption) (*MultipleProfilesResponse, error)
+	// GetMultipleFullProfiles returns profiles with their prompts; at most 50 IDs are accepted.
+	GetMultipleFullProfiles(ctx context.Context, in *GetMultipleProfilesRequest, opts ...grpc.CallOption) (*MultipleFullProfilesResponse, error)
 	GetRandomProfilePreferredByUser(ctx context.Context, in *GetRandomProfilePreferredByUserRequest, opts ...grpc.CallOption) (*FullProfileResponse, error)
 	GetFullProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*FullProfileResponse, error)
 	GetPrompts(ctx context.Context, in *GetPromptsRequest, opts ...grpc.CallOption) (*PromptsResponse, error)
 	AddPrompts(ctx context.Context, in *AddPromptsRequest, opts ...grpc.CallOption) (*PromptsResponse, error)
 	AddFilePrompt(ctx context.Context, in *AddFilePromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
 	UpdateFilePrompt(ctx context.Context, in *UpdateFilePromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	AddMediaPrompt(ctx context.Context, in *AddMediaPromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	UpdateMediaPrompt(ctx context.Context, in *UpdateMediaPromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	// UploadFilePrompt expects a header followed by the content chunks.
+	UploadFilePrompt(ctx context.Context, opts ...grpc.CallOption) (ProfileService_UploadFilePromptClient, error)
 	UpdatePrompt(ctx context.Context, in *UpdatePromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
 	UpdatePromptsPositions(ctx context.Context, in *UpdatePromptsPositionsRequest, opts ...grpc.CallOption) (*PromptsResponse, error)
 	DeletePrompt(ctx context.Context, in *DeletePromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	// SearchPrompts finds prompts by the words in their questions and text answers.
+	SearchPrompts(ctx context.Context, in *SearchPromptsRequest, opts ...grpc.CallOption) (*SearchPromptsResponse, error)
+	// Gallery prompts are created empty with AddPrompts and type gallery.
+	AddGalleryImage(ctx context.Context, in *AddGalleryImageRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	RemoveGalleryImage(ctx context.Context, in *RemoveGalleryImageRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	ReorderGalleryImages(ctx context.Context, in *ReorderGalleryImagesRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error)
+	BlockUser(ctx context.Context, in *BlockUserRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	UnblockUser(ctx context.Context, in *BlockUserRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	ListBlockedUsers(ctx context.Context, in *ListBlockedUsersRequest, opts ...grpc.CallOption) (*BlockedUsersResponse, error)
+	SetVisibility(ctx context.Context, in *SetVisibilityRequest, opts ...grpc.CallOption) (*ProfileResponse, error)
+	AllowViewer(ctx context.Context, in *ViewerRequest, opts ...grpc.CallOption) (*AllowedViewerResponse, error)
+	DisallowViewer(ctx context.Context, in *ViewerRequest, opts ...grpc.CallOption) (*AllowedViewerResponse, error)
+	ListAllowedViewers(ctx context.Context, in *ListAllowedViewersRequest, opts ...grpc.CallOption) (*AllowedViewersResponse, error)
+	SetMainPicture(ctx context.Context, in *SetMainPictureRequest, opts ...grpc.CallOption) (*ProfileResponse, error)
+	ReportProfile(ctx context.Context, in *ReportProfileRequest, opts ...grpc.CallOption) (*ReportResponse, error)
+	// Moderation RPCs intended for admin tooling.
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ReportsResponse, error)
+	ClaimReport(ctx context.Context, in *ClaimReportRequest, opts ...grpc.CallOption) (*ReportResponse, error)
+	ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*ReportResponse, error)
+	// ListProfiles pages through all profiles, including suspended and hidden ones.
+	ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ProfilesPageResponse, error)
+	ListPromptQuestions(ctx context.Context, in *ListPromptQuestionsRequest, opts ...grpc.CallOption) (*PromptQuestionsResponse, error)
+	// Catalog management RPCs intended for admin tooling.
+	CreatePromptQuestion(ctx context.Context, in *PromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error)
+	UpdatePromptQuestion(ctx context.Context, in *PromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error)
+	DeletePromptQuestion(ctx context.Context, in *DeletePromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error)
 }
 
 type profileServiceClient struct {
@@ -81,6 +113,15 @@ func (c *profileServiceClient) GetMultipleProfiles(ctx context.Context, in *GetM
 	return out, nil
 }
 
+func (c *profileServiceClient) GetMultipleFullProfiles(ctx context.Context, in *GetMultipleProfilesRequest, opts ...grpc.CallOption) (*MultipleFullProfilesResponse, error) {
+	out := new(MultipleFullProfilesResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/GetMultipleFullProfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *profileServiceClient) GetRandomProfilePreferredByUser(ctx context.Context, in *GetRandomProfilePreferredByUserRequest, opts ...grpc.CallOption) (*FullProfileResponse, error) {
 	out := new(FullProfileResponse)
 	err := c.cc.Invoke(ctx, "/profiles.ProfileService/GetRandomProfilePreferredByUser", in, out, opts...)
@@ -135,6 +176,58 @@ func (c *profileServiceClient) UpdateFilePrompt(ctx context.Context, in *UpdateF
 	return out, nil
 }
 
+func (c *profileServiceClient) AddMediaPrompt(ctx context.Context, in *AddMediaPromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
+	out := new(SinglePromptResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/AddMediaPrompt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) UpdateMediaPrompt(ctx context.Context, in *UpdateMediaPromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
+	out := new(SinglePromptResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/UpdateMediaPrompt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) UploadFilePrompt(ctx context.Context, opts ...grpc.CallOption) (ProfileService_UploadFilePromptClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProfileService_ServiceDesc.Streams[0], "/profiles.ProfileService/UploadFilePrompt", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &profileServiceUploadFilePromptClient{stream}
+	return x, nil
+}
+
+type ProfileService_UploadFilePromptClient interface {
+	Send(*UploadFilePromptRequest) error
+	CloseAndRecv() (*SinglePromptResponse, error)
+	grpc.ClientStream
+}
+
+type profileServiceUploadFilePromptClient struct {
+	grpc.ClientStream
+}
+
+func (x *profileServiceUploadFilePromptClient) Send(m *UploadFilePromptRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *profileServiceUploadFilePromptClient) CloseAndRecv() (*SinglePromptResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SinglePromptResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *profileServiceClient) UpdatePrompt(ctx context.Context, in *UpdatePromptRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
 	out := new(SinglePromptResponse)
 	err := c.cc.Invoke(ctx, "/profiles.ProfileService/UpdatePrompt", in, out, opts...)
@@ -162,6 +255,195 @@ func (c *profileServiceClient) DeletePrompt(ctx context.Context, in *DeletePromp
 	return out, nil
 }
 
+func (c *profileServiceClient) SearchPrompts(ctx context.Context, in *SearchPromptsRequest, opts ...grpc.CallOption) (*SearchPromptsResponse, error) {
+	out := new(SearchPromptsResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/SearchPrompts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) AddGalleryImage(ctx context.Context, in *AddGalleryImageRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
+	out := new(SinglePromptResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/AddGalleryImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) RemoveGalleryImage(ctx context.Context, in *RemoveGalleryImageRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
+	out := new(SinglePromptResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/RemoveGalleryImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ReorderGalleryImages(ctx context.Context, in *ReorderGalleryImagesRequest, opts ...grpc.CallOption) (*SinglePromptResponse, error) {
+	out := new(SinglePromptResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ReorderGalleryImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) BlockUser(ctx context.Context, in *BlockUserRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/BlockUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) UnblockUser(ctx context.Context, in *BlockUserRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/UnblockUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ListBlockedUsers(ctx context.Context, in *ListBlockedUsersRequest, opts ...grpc.CallOption) (*BlockedUsersResponse, error) {
+	out := new(BlockedUsersResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ListBlockedUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) SetVisibility(ctx context.Context, in *SetVisibilityRequest, opts ...grpc.CallOption) (*ProfileResponse, error) {
+	out := new(ProfileResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/SetVisibility", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) AllowViewer(ctx context.Context, in *ViewerRequest, opts ...grpc.CallOption) (*AllowedViewerResponse, error) {
+	out := new(AllowedViewerResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/AllowViewer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) DisallowViewer(ctx context.Context, in *ViewerRequest, opts ...grpc.CallOption) (*AllowedViewerResponse, error) {
+	out := new(AllowedViewerResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/DisallowViewer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ListAllowedViewers(ctx context.Context, in *ListAllowedViewersRequest, opts ...grpc.CallOption) (*AllowedViewersResponse, error) {
+	out := new(AllowedViewersResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ListAllowedViewers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) SetMainPicture(ctx context.Context, in *SetMainPictureRequest, opts ...grpc.CallOption) (*ProfileResponse, error) {
+	out := new(ProfileResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/SetMainPicture", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ReportProfile(ctx context.Context, in *ReportProfileRequest, opts ...grpc.CallOption) (*ReportResponse, error) {
+	out := new(ReportResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ReportProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ReportsResponse, error) {
+	out := new(ReportsResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ListReports", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ClaimReport(ctx context.Context, in *ClaimReportRequest, opts ...grpc.CallOption) (*ReportResponse, error) {
+	out := new(ReportResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ClaimReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*ReportResponse, error) {
+	out := new(ReportResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ResolveReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ProfilesPageResponse, error) {
+	out := new(ProfilesPageResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ListProfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) ListPromptQuestions(ctx context.Context, in *ListPromptQuestionsRequest, opts ...grpc.CallOption) (*PromptQuestionsResponse, error) {
+	out := new(PromptQuestionsResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/ListPromptQuestions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) CreatePromptQuestion(ctx context.Context, in *PromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error) {
+	out := new(PromptQuestionResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/CreatePromptQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) UpdatePromptQuestion(ctx context.Context, in *PromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error) {
+	out := new(PromptQuestionResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/UpdatePromptQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileServiceClient) DeletePromptQuestion(ctx context.Context, in *DeletePromptQuestionRequest, opts ...grpc.CallOption) (*PromptQuestionResponse, error) {
+	out := new(PromptQuestionResponse)
+	err := c.cc.Invoke(ctx, "/profiles.ProfileService/DeletePromptQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProfileServiceServer is the server API for ProfileService service.
 // All implementations must embed UnimplementedProfileServiceServer
 // for forward compatibility
@@ -170,15 +452,47 @@ type ProfileServiceServer interface {
 	GetProfile(context.Context, *GetProfileRequest) (*ProfileResponse, error)
 	UpdateProfile(context.Context, *UpdateProfileRequest) (*ProfileResponse, error)
 	GetMultipleProfiles(context.Context, *GetMultipleProfilesRequest) (*MultipleProfilesResponse, error)
+	// GetMultipleFullProfiles returns profiles with their prompts; at most 50 IDs are accepted.
+	GetMultipleFullProfiles(context.Context, *GetMultipleProfilesRequest) (*MultipleFullProfilesResponse, error)
 	GetRandomProfilePreferredByUser(context.Context, *GetRandomProfilePreferredByUserRequest) (*FullProfileResponse, error)
 	GetFullProfile(context.Context, *GetProfileRequest) (*FullProfileResponse, error)
 	GetPrompts(context.Context, *GetPromptsRequest) (*PromptsResponse, error)
 	AddPrompts(context.Context, *AddPromptsRequest) (*PromptsResponse, error)
 	AddFilePrompt(context.Context, *AddFilePromptRequest) (*SinglePromptResponse, error)
 	UpdateFilePrompt(context.Context, *UpdateFilePromptRequest) (*SinglePromptResponse, error)
+	AddMediaPrompt(context.Context, *AddMediaPromptRequest) (*SinglePromptResponse, error)
+	UpdateMediaPrompt(context.Context, *UpdateMediaPromptRequest) (*SinglePromptResponse, error)
+	// UploadFilePrompt expects a header followed by the content chunks.
+	UploadFilePrompt(ProfileService_UploadFilePromptServer) error
 	UpdatePrompt(context.Context, *UpdatePromptRequest) (*SinglePromptResponse, error)
 	UpdatePromptsPositions(context.Context, *UpdatePromptsPositionsRequest) (*PromptsResponse, error)
 	DeletePrompt(context.Context, *DeletePromptRequest) (*SinglePromptResponse, error)
+	// SearchPrompts finds prompts by the words in their questions and text answers.
+	SearchPrompts(context.Context, *SearchPromptsRequest) (*SearchPromptsResponse, error)
+	// Gallery prompts are created empty with AddPrompts and type gallery.
+	AddGalleryImage(context.Context, *AddGalleryImageRequest) (*SinglePromptResponse, error)
+	RemoveGalleryImage(context.Context, *RemoveGalleryImageRequest) (*SinglePromptResponse, error)
+	ReorderGalleryImages(context.Context, *ReorderGalleryImagesRequest) (*SinglePromptResponse, error)
+	BlockUser(context.Context, *BlockUserRequest) (*BlockResponse, error)
+	UnblockUser(context.Context, *BlockUserRequest) (*BlockResponse, error)
+	ListBlockedUsers(context.Context, *ListBlockedUsersRequest) (*BlockedUsersResponse, error)
+	SetVisibility(context.Context, *SetVisibilityRequest) (*ProfileResponse, error)
+	AllowViewer(context.Context, *ViewerRequest) (*AllowedViewerResponse, error)
+	DisallowViewer(context.Context, *ViewerRequest) (*AllowedViewerResponse, error)
+	ListAllowedViewers(context.Context, *ListAllowedViewersRequest) (*AllowedViewersResponse, error)
+	SetMainPicture(context.Context, *SetMainPictureRequest) (*ProfileResponse, error)
+	ReportProfile(context.Context, *ReportProfileRequest) (*ReportResponse, error)
+	// Moderation RPCs intended for admin tooling.
+	ListReports(context.Context, *ListReportsRequest) (*ReportsResponse, error)
+	ClaimReport(context.Context, *ClaimReportRequest) (*ReportResponse, error)
+	ResolveReport(context.Context, *ResolveReportRequest) (*ReportResponse, error)
+	// ListProfiles pages through all profiles, including suspended and hidden ones.
+	ListProfiles(context.Context, *ListProfilesRequest) (*ProfilesPageResponse, error)
+	ListPromptQuestions(context.Context, *ListPromptQuestionsRequest) (*PromptQuestionsResponse, error)
+	// Catalog management RPCs intended for admin tooling.
+	CreatePromptQuestion(context.Context, *PromptQuestionRequest) (*PromptQuestionResponse, error)
+	UpdatePromptQuestion(context.Context, *PromptQuestionRequest) (*PromptQuestionResponse, error)
+	DeletePromptQuestion(context.Context, *DeletePromptQuestionRequest) (*PromptQuestionResponse, error)
 	mustEmbedUnimplementedProfileServiceServer()
 }
 
@@ -198,6 +512,9 @@ func (UnimplementedProfileServiceServer) UpdateProfile(context.Context, *UpdateP
 func (UnimplementedProfileServiceServer) GetMultipleProfiles(context.Context, *GetMultipleProfilesRequest) (*MultipleProfilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMultipleProfiles not implemented")
 }
+func (UnimplementedProfileServiceServer) GetMultipleFullProfiles(context.Context, *GetMultipleProfilesRequest) (*MultipleFullProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMultipleFullProfiles not implemented")
+}
 func (UnimplementedProfileServiceServer) GetRandomProfilePreferredByUser(context.Context, *GetRandomProfilePreferredByUserRequest) (*FullProfileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRandomProfilePreferredByUser not implemented")
 }
@@ -216,6 +533,15 @@ func (UnimplementedProfileServiceServer) AddFilePrompt(context.Context, *AddFile
 func (UnimplementedProfileServiceServer) UpdateFilePrompt(context.Context, *UpdateFilePromptRequest) (*SinglePromptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateFilePrompt not implemented")
 }
+func (UnimplementedProfileServiceServer) AddMediaPrompt(context.Context, *AddMediaPromptRequest) (*SinglePromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMediaPrompt not implemented")
+}
+func (UnimplementedProfileServiceServer) UpdateMediaPrompt(context.Context, *UpdateMediaPromptRequest) (*SinglePromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMediaPrompt not implemented")
+}
+func (UnimplementedProfileServiceServer) UploadFilePrompt(ProfileService_UploadFilePromptServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFilePrompt not implemented")
+}
 func (UnimplementedProfileServiceServer) UpdatePrompt(context.Context, *UpdatePromptRequest) (*SinglePromptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePrompt not implemented")
 }
@@ -225,6 +551,69 @@ func (UnimplementedProfileServiceServer) UpdatePromptsPositions(context.Context,
 func (UnimplementedProfileServiceServer) DeletePrompt(context.Context, *DeletePromptRequest) (*SinglePromptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeletePrompt not implemented")
 }
+func (UnimplementedProfileServiceServer) SearchPrompts(context.Context, *SearchPromptsRequest) (*SearchPromptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPrompts not implemented")
+}
+func (UnimplementedProfileServiceServer) AddGalleryImage(context.Context, *AddGalleryImageRequest) (*SinglePromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGalleryImage not implemented")
+}
+func (UnimplementedProfileServiceServer) RemoveGalleryImage(context.Context, *RemoveGalleryImageRequest) (*SinglePromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGalleryImage not implemented")
+}
+func (UnimplementedProfileServiceServer) ReorderGalleryImages(context.Context, *ReorderGalleryImagesRequest) (*SinglePromptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderGalleryImages not implemented")
+}
+func (UnimplementedProfileServiceServer) BlockUser(context.Context, *BlockUserRequest) (*BlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockUser not implemented")
+}
+func (UnimplementedProfileServiceServer) UnblockUser(context.Context, *BlockUserRequest) (*BlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockUser not implemented")
+}
+func (UnimplementedProfileServiceServer) ListBlockedUsers(context.Context, *ListBlockedUsersRequest) (*BlockedUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockedUsers not implemented")
+}
+func (UnimplementedProfileServiceServer) SetVisibility(context.Context, *SetVisibilityRequest) (*ProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVisibility not implemented")
+}
+func (UnimplementedProfileServiceServer) AllowViewer(context.Context, *ViewerRequest) (*AllowedViewerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllowViewer not implemented")
+}
+func (UnimplementedProfileServiceServer) DisallowViewer(context.Context, *ViewerRequest) (*AllowedViewerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisallowViewer not implemented")
+}
+func (UnimplementedProfileServiceServer) ListAllowedViewers(context.Context, *ListAllowedViewersRequest) (*AllowedViewersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAllowedViewers not implemented")
+}
+func (UnimplementedProfileServiceServer) SetMainPicture(context.Context, *SetMainPictureRequest) (*ProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMainPicture not implemented")
+}
+func (UnimplementedProfileServiceServer) ReportProfile(context.Context, *ReportProfileRequest) (*ReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportProfile not implemented")
+}
+func (UnimplementedProfileServiceServer) ListReports(context.Context, *ListReportsRequest) (*ReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedProfileServiceServer) ClaimReport(context.Context, *ClaimReportRequest) (*ReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimReport not implemented")
+}
+func (UnimplementedProfileServiceServer) ResolveReport(context.Context, *ResolveReportRequest) (*ReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveReport not implemented")
+}
+func (UnimplementedProfileServiceServer) ListProfiles(context.Context, *ListProfilesRequest) (*ProfilesPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProfiles not implemented")
+}
+func (UnimplementedProfileServiceServer) ListPromptQuestions(context.Context, *ListPromptQuestionsRequest) (*PromptQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPromptQuestions not implemented")
+}
+func (UnimplementedProfileServiceServer) CreatePromptQuestion(context.Context, *PromptQuestionRequest) (*PromptQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePromptQuestion not implemented")
+}
+func (UnimplementedProfileServiceServer) UpdatePromptQuestion(context.Context, *PromptQuestionRequest) (*PromptQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePromptQuestion not implemented")
+}
+func (UnimplementedProfileServiceServer) DeletePromptQuestion(context.Context, *DeletePromptQuestionRequest) (*PromptQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePromptQuestion not implemented")
+}
 func (UnimplementedProfileServiceServer) mustEmbedUnimplementedProfileServiceServer() {}
 
 // UnsafeProfileServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -310,6 +699,24 @@ func _ProfileService_GetMultipleProfiles_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProfileService_GetMultipleFullProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMultipleProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).GetMultipleFullProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/GetMultipleFullProfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).GetMultipleFullProfiles(ctx, req.(*GetMultipleProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProfileService_GetRandomProfilePreferredByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRandomProfilePreferredByUserRequest)
 	if err := dec(in); err != nil {
@@ -418,6 +825,68 @@ func _ProfileService_UpdateFilePrompt_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProfileService_AddMediaPrompt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddMediaPromptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).AddMediaPrompt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/AddMediaPrompt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).AddMediaPrompt(ctx, req.(*AddMediaPromptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_UpdateMediaPrompt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMediaPromptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).UpdateMediaPrompt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/UpdateMediaPrompt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).UpdateMediaPrompt(ctx, req.(*UpdateMediaPromptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_UploadFilePrompt_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ProfileServiceServer).UploadFilePrompt(&profileServiceUploadFilePromptServer{stream})
+}
+
+type ProfileService_UploadFilePromptServer interface {
+	SendAndClose(*SinglePromptResponse) error
+	Recv() (*UploadFilePromptRequest, error)
+	grpc.ServerStream
+}
+
+type profileServiceUploadFilePromptServer struct {
+	grpc.ServerStream
+}
+
+func (x *profileServiceUploadFilePromptServer) SendAndClose(m *SinglePromptResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *profileServiceUploadFilePromptServer) Recv() (*UploadFilePromptRequest, error) {
+	m := new(UploadFilePromptRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _ProfileService_UpdatePrompt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdatePromptRequest)
 	if err := dec(in); err != nil {
@@ -472,17 +941,395 @@ func _ProfileService_DeletePrompt_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-// ProfileService_ServiceDesc is the grpc.ServiceDesc for ProfileService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var ProfileService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "profiles.ProfileService",
-	HandlerType: (*ProfileServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateProfile",
-			Handler:    _ProfileService_CreateProfile_Handler,
-		},
+func _ProfileService_SearchPrompts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPromptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).SearchPrompts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/SearchPrompts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).SearchPrompts(ctx, req.(*SearchPromptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_AddGalleryImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGalleryImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).AddGalleryImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/AddGalleryImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).AddGalleryImage(ctx, req.(*AddGalleryImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_RemoveGalleryImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveGalleryImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).RemoveGalleryImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/RemoveGalleryImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).RemoveGalleryImage(ctx, req.(*RemoveGalleryImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ReorderGalleryImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderGalleryImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ReorderGalleryImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ReorderGalleryImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ReorderGalleryImages(ctx, req.(*ReorderGalleryImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_BlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).BlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/BlockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).BlockUser(ctx, req.(*BlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_UnblockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).UnblockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/UnblockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).UnblockUser(ctx, req.(*BlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ListBlockedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockedUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ListBlockedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ListBlockedUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ListBlockedUsers(ctx, req.(*ListBlockedUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_SetVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).SetVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/SetVisibility",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).SetVisibility(ctx, req.(*SetVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_AllowViewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ViewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).AllowViewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/AllowViewer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).AllowViewer(ctx, req.(*ViewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_DisallowViewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ViewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).DisallowViewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/DisallowViewer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).DisallowViewer(ctx, req.(*ViewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ListAllowedViewers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAllowedViewersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ListAllowedViewers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ListAllowedViewers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ListAllowedViewers(ctx, req.(*ListAllowedViewersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_SetMainPicture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMainPictureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).SetMainPicture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/SetMainPicture",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).SetMainPicture(ctx, req.(*SetMainPictureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ReportProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ReportProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ReportProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ReportProfile(ctx, req.(*ReportProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ListReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ClaimReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ClaimReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ClaimReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ClaimReport(ctx, req.(*ClaimReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ResolveReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ResolveReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ResolveReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ResolveReport(ctx, req.(*ResolveReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ListProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ListProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ListProfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ListProfiles(ctx, req.(*ListProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_ListPromptQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPromptQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).ListPromptQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/ListPromptQuestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).ListPromptQuestions(ctx, req.(*ListPromptQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_CreatePromptQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromptQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).CreatePromptQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/CreatePromptQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).CreatePromptQuestion(ctx, req.(*PromptQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_UpdatePromptQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromptQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).UpdatePromptQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/UpdatePromptQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).UpdatePromptQuestion(ctx, req.(*PromptQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProfileService_DeletePromptQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePromptQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).DeletePromptQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/profiles.ProfileService/DeletePromptQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).DeletePromptQuestion(ctx, req.(*DeletePromptQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProfileService_ServiceDesc is the grpc.ServiceDesc for ProfileService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProfileService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "profiles.ProfileService",
+	HandlerType: (*ProfileServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateProfile",
+			Handler:    _ProfileService_CreateProfile_Handler,
+		},
 		{
 			MethodName: "GetProfile",
 			Handler:    _ProfileService_GetProfile_Handler,
@@ -495,6 +1342,10 @@ var ProfileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMultipleProfiles",
 			Handler:    _ProfileService_GetMultipleProfiles_Handler,
 		},
+		{
+			MethodName: "GetMultipleFullProfiles",
+			Handler:    _ProfileService_GetMultipleFullProfiles_Handler,
+		},
 		{
 			MethodName: "GetRandomProfilePreferredByUser",
 			Handler:    _ProfileService_GetRandomProfilePreferredByUser_Handler,
@@ -519,6 +1370,14 @@ var ProfileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateFilePrompt",
 			Handler:    _ProfileService_UpdateFilePrompt_Handler,
 		},
+		{
+			MethodName: "AddMediaPrompt",
+			Handler:    _ProfileService_AddMediaPrompt_Handler,
+		},
+		{
+			MethodName: "UpdateMediaPrompt",
+			Handler:    _ProfileService_UpdateMediaPrompt_Handler,
+		},
 		{
 			MethodName: "UpdatePrompt",
 			Handler:    _ProfileService_UpdatePrompt_Handler,
@@ -531,7 +1390,97 @@ var ProfileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeletePrompt",
 			Handler:    _ProfileService_DeletePrompt_Handler,
 		},
+		{
+			MethodName: "SearchPrompts",
+			Handler:    _ProfileService_SearchPrompts_Handler,
+		},
+		{
+			MethodName: "AddGalleryImage",
+			Handler:    _ProfileService_AddGalleryImage_Handler,
+		},
+		{
+			MethodName: "RemoveGalleryImage",
+			Handler:    _ProfileService_RemoveGalleryImage_Handler,
+		},
+		{
+			MethodName: "ReorderGalleryImages",
+			Handler:    _ProfileService_ReorderGalleryImages_Handler,
+		},
+		{
+			MethodName: "BlockUser",
+			Handler:    _ProfileService_BlockUser_Handler,
+		},
+		{
+			MethodName: "UnblockUser",
+			Handler:    _ProfileService_UnblockUser_Handler,
+		},
+		{
+			MethodName: "ListBlockedUsers",
+			Handler:    _ProfileService_ListBlockedUsers_Handler,
+		},
+		{
+			MethodName: "SetVisibility",
+			Handler:    _ProfileService_SetVisibility_Handler,
+		},
+		{
+			MethodName: "AllowViewer",
+			Handler:    _ProfileService_AllowViewer_Handler,
+		},
+		{
+			MethodName: "DisallowViewer",
+			Handler:    _ProfileService_DisallowViewer_Handler,
+		},
+		{
+			MethodName: "ListAllowedViewers",
+			Handler:    _ProfileService_ListAllowedViewers_Handler,
+		},
+		{
+			MethodName: "SetMainPicture",
+			Handler:    _ProfileService_SetMainPicture_Handler,
+		},
+		{
+			MethodName: "ReportProfile",
+			Handler:    _ProfileService_ReportProfile_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _ProfileService_ListReports_Handler,
+		},
+		{
+			MethodName: "ClaimReport",
+			Handler:    _ProfileService_ClaimReport_Handler,
+		},
+		{
+			MethodName: "ResolveReport",
+			Handler:    _ProfileService_ResolveReport_Handler,
+		},
+		{
+			MethodName: "ListProfiles",
+			Handler:    _ProfileService_ListProfiles_Handler,
+		},
+		{
+			MethodName: "ListPromptQuestions",
+			Handler:    _ProfileService_ListPromptQuestions_Handler,
+		},
+		{
+			MethodName: "CreatePromptQuestion",
+			Handler:    _ProfileService_CreatePromptQuestion_Handler,
+		},
+		{
+			MethodName: "UpdatePromptQuestion",
+			Handler:    _ProfileService_UpdatePromptQuestion_Handler,
+		},
+		{
+			MethodName: "DeletePromptQuestion",
+			Handler:    _ProfileService_DeletePromptQuestion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadFilePrompt",
+			Handler:       _ProfileService_UploadFilePrompt_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/ports/grpc/profiles.proto",
 }
@@ -3,7 +3,7 @@ package grpc
 import (
 	"context"
 	"github.com/soulmate-dating/profiles/internal/ports/http"
-	"log"
+	"log/slog"
 	"net"
 	"os"
 
@@ -14,6 +14,7 @@ import (
 	"github.com/soulmate-dating/profiles/internal/app"
 	"github.com/soulmate-dating/profiles/internal/config"
 	"github.com/soulmate-dating/profiles/internal/graceful"
+	"github.com/soulmate-dating/profiles/internal/medialinks"
 )
 
 const MB = 1024 * 1024
@@ -21,15 +22,28 @@ const MB = 1024 * 1024
 func Run(ctx context.Context, cfg config.Config, app app.App) {
 	lis, err := net.Listen(cfg.API.Network, cfg.API.Address)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		slog.Error("failed to listen", "address", cfg.API.Address, "error", err)
+		os.Exit(1)
 	}
 
-	svc := NewService(app)
+	links := medialinks.NewSigner(medialinks.Config{
+		BaseURL:    cfg.MediaLinks.BaseURL,
+		CDNBaseURL: cfg.MediaLinks.CDNBaseURL,
+		Secret:     cfg.MediaLinks.Secret,
+		TTL:        cfg.MediaLinks.TTL,
+	})
+	svc := NewService(app, cfg.API.MaxUploadSize*MB, links)
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			UnaryRequestIDInterceptor,
 			UnaryLoggerInterceptor,
 			UnaryRecoveryInterceptor(),
 		),
+		grpc.ChainStreamInterceptor(
+			StreamRequestIDInterceptor,
+			StreamLoggerInterceptor,
+			StreamRecoveryInterceptor(),
+		),
 		grpc.MaxRecvMsgSize(cfg.API.MaxReceiveSize*MB),
 		grpc.MaxSendMsgSize(cfg.API.MaxSendSize*MB),
 		grpc.StreamInterceptor(grpcProm.StreamServerInterceptor),
@@ -47,7 +61,7 @@ func Run(ctx context.Context, cfg config.Config, app app.App) {
 	eg.Go(http.RunServer(ctx, s))
 
 	if err := eg.Wait(); err != nil {
-		log.Printf("gracefully shutting down the servers: %s\n", err.Error())
+		slog.Info("gracefully shutting down the servers", "reason", err.Error())
 	}
-	log.Println("servers were successfully shutdown")
+	slog.Info("servers were successfully shutdown")
 }
@@ -3,60 +3,218 @@ package grpc
 import (
 	"context"
 	"fmt"
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpcRecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/soulmate-dating/profiles/internal/app"
+	"github.com/soulmate-dating/profiles/internal/requestid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"log/slog"
 	"net"
 	"runtime/debug"
+	"strconv"
 	"time"
 )
 
 type ProfileService struct {
-	app app.App
+	app           app.App
+	maxUploadSize int
+	links         LinkResolver
 }
 
 func (s *ProfileService) mustEmbedUnimplementedProfileServiceServer() {}
 
-func NewService(a app.App) ProfileServiceServer {
-	service := &ProfileService{app: a}
+// linksFor returns the resolver of the response media links, preferring
+// the renditions of the image size.
+func (s *ProfileService) linksFor(size string) mediaLinks {
+	return mediaLinks{resolver: s.links, size: size}
+}
+
+func NewService(a app.App, maxUploadSize int, links LinkResolver) ProfileServiceServer {
+	service := &ProfileService{app: a, maxUploadSize: maxUploadSize, links: links}
 	return service
 }
 
+// UnaryRequestIDInterceptor reuses the x-request-id sent by the caller or
+// generates a new one when it is missing or invalid, stores it in the
+// context and echoes it back in the response headers.
+func UnaryRequestIDInterceptor(ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	ctx = withRequestID(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, requestid.FromContext(ctx)))
+
+	return handler(ctx, req)
+}
+
+// StreamRequestIDInterceptor is the streaming counterpart of UnaryRequestIDInterceptor.
+func StreamRequestIDInterceptor(srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	wrapped := grpcMiddleware.WrapServerStream(stream)
+	wrapped.WrappedContext = withRequestID(stream.Context())
+	_ = stream.SetHeader(metadata.Pairs(requestid.Header, requestid.FromContext(wrapped.WrappedContext)))
+
+	return handler(srv, wrapped)
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.Header); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	// The ID is logged and forwarded to other services.
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+	return requestid.NewContext(ctx, id)
+}
+
 func UnaryLoggerInterceptor(ctx context.Context,
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler) (interface{}, error) {
 
-	start := time.Now()
-	log.Printf("-- received request -- | protocol: GRPC | method: %s", info.FullMethod)
+	attrs := []any{slog.String("protocol", "grpc"), slog.String("method", info.FullMethod)}
+	if userId := requestUserId(req); userId != "" {
+		attrs = append(attrs, slog.String("user_id", userId))
+	}
+	if m, ok := req.(proto.Message); ok && slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.DebugContext(ctx, "received request", append(attrs, slog.Group("request", messageAttrs(m.ProtoReflect())...))...)
+	}
 
+	start := time.Now()
 	h, err := handler(ctx, req)
 
-	latency := time.Since(start)
-	log.Printf("-- handled request -- | protocol: GRPC | latency: %+v | method: %s | error: (%v)\n",
-		latency, info.FullMethod, err)
+	attrs = append(attrs,
+		slog.Duration("latency", time.Since(start)),
+		slog.String("code", status.Code(err).String()),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "handled request", append(attrs, slog.String("error", err.Error()))...)
+	} else {
+		slog.InfoContext(ctx, "handled request", attrs...)
+	}
 
 	return h, err
 }
 
+// StreamLoggerInterceptor logs streaming calls. Stream messages are not
+// logged since they carry file chunks.
+func StreamLoggerInterceptor(srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	ctx := stream.Context()
+	start := time.Now()
+	err := handler(srv, stream)
+
+	attrs := []any{
+		slog.String("protocol", "grpc"),
+		slog.String("method", info.FullMethod),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("code", status.Code(err).String()),
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "handled stream", append(attrs, slog.String("error", err.Error()))...)
+	} else {
+		slog.InfoContext(ctx, "handled stream", attrs...)
+	}
+
+	return err
+}
+
 func UnaryRecoveryInterceptor() grpc.UnaryServerInterceptor {
-	stackTraceLogger := grpcRecovery.WithRecoveryHandlerContext(
+	return grpcRecovery.UnaryServerInterceptor(recoveryHandler())
+}
+
+func StreamRecoveryInterceptor() grpc.StreamServerInterceptor {
+	return grpcRecovery.StreamServerInterceptor(recoveryHandler())
+}
+
+func recoveryHandler() grpcRecovery.Option {
+	return grpcRecovery.WithRecoveryHandlerContext(
 		func(ctx context.Context, p interface{}) error {
-			fmt.Print("\n\n")
-			log.Printf("[PANIC] %s\n%s\n", p, string(debug.Stack()))
+			slog.ErrorContext(ctx, "recovered from panic",
+				slog.Any("panic", p),
+				slog.String("stack", string(debug.Stack())),
+			)
 			return status.Errorf(codes.Internal, "%s", p)
 		},
 	)
-	return grpcRecovery.UnaryServerInterceptor(stackTraceLogger)
+}
+
+func requestUserId(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetUserId() string }:
+		return r.GetUserId()
+	case *CreateProfileRequest:
+		return r.GetId()
+	case *GetProfileRequest:
+		return r.GetId()
+	case *UpdateProfileRequest:
+		return r.GetId()
+	}
+	return ""
+}
+
+// messageAttrs converts a protobuf message into log attributes keyed by
+// field name, so that personal fields can be redacted by the logger.
+// Binary payloads are replaced with their size.
+func messageAttrs(m protoreflect.Message) []any {
+	var attrs []any
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		attrs = append(attrs, fieldAttr(fd, v))
+		return true
+	})
+	return attrs
+}
+
+func fieldAttr(fd protoreflect.FieldDescriptor, v protoreflect.Value) slog.Attr {
+	key := string(fd.Name())
+	switch {
+	case fd.IsMap():
+		return slog.Int(key+"_count", v.Map().Len())
+	case fd.IsList():
+		list := v.List()
+		items := make([]any, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items[i] = valueAttr(fd, strconv.Itoa(i), list.Get(i))
+		}
+		return slog.Group(key, items...)
+	}
+	return valueAttr(fd, key, v)
+}
+
+func valueAttr(fd protoreflect.FieldDescriptor, key string, v protoreflect.Value) slog.Attr {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return slog.Group(key, messageAttrs(v.Message())...)
+	case protoreflect.BytesKind:
+		return slog.Int(key+"_size", len(v.Bytes()))
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return slog.String(key, string(ev.Name()))
+		}
+	}
+	return slog.Any(key, v.Interface())
 }
 
 func RunGRPCServerGracefully(ctx context.Context, lis net.Listener, server *grpc.Server) func() error {
 	return func() error {
-		log.Printf("starting grpc server, listening on %s\n", lis.Addr())
-		defer log.Printf("close grpc server listening on %s\n", lis.Addr())
+		slog.Info("starting grpc server", "address", lis.Addr().String())
+		defer slog.Info("close grpc server", "address", lis.Addr().String())
 
 		errCh := make(chan error)
 
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxUploadPreallocation is the largest buffer allocated for an upload before
+// its content is received.
+const maxUploadPreallocation = 1 * MB
+
+// receiveUpload reads the header and the content chunks of a streamed
+// upload. The content is rejected as soon as it exceeds the announced size
+// or maxSize, and its checksum is verified once the stream ends.
+func receiveUpload(stream ProfileService_UploadFilePromptServer, maxSize int) (*UploadFilePromptHeader, []byte, error) {
+	request, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, status.Error(codes.InvalidArgument, "missing upload header")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	header := request.GetHeader()
+	if header == nil {
+		return nil, nil, status.Error(codes.InvalidArgument, "first message must be the upload header")
+	}
+	if header.GetSize() <= 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "upload size must be positive")
+	}
+	if header.GetSize() > int64(maxSize) {
+		return nil, nil, status.Errorf(codes.ResourceExhausted, "upload size exceeds %d bytes", maxSize)
+	}
+	checksum, err := hex.DecodeString(header.GetSha256())
+	if err != nil || len(checksum) != sha256.Size {
+		return nil, nil, status.Error(codes.InvalidArgument, "invalid sha256 checksum")
+	}
+
+	// The announced size is not trusted with memory until the bytes arrive:
+	// streams announcing large files and stalling would otherwise hold
+	// maxSize bytes each.
+	content := bytes.NewBuffer(make([]byte, 0, min(header.GetSize(), maxUploadPreallocation)))
+	for {
+		request, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if request.GetHeader() != nil {
+			return nil, nil, status.Error(codes.InvalidArgument, "upload header sent twice")
+		}
+		if int64(content.Len()+len(request.GetChunk())) > header.GetSize() {
+			return nil, nil, status.Error(codes.InvalidArgument, "upload is larger than the announced size")
+		}
+		content.Write(request.GetChunk())
+	}
+
+	if int64(content.Len()) != header.GetSize() {
+		return nil, nil, status.Error(codes.InvalidArgument,
+			fmt.Sprintf("received %d of %d bytes", content.Len(), header.GetSize()))
+	}
+	sum := sha256.Sum256(content.Bytes())
+	if !bytes.Equal(sum[:], checksum) {
+		return nil, nil, status.Error(codes.DataLoss, "checksum mismatch")
+	}
+	return header, content.Bytes(), nil
+}
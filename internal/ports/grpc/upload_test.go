@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type uploadStream struct {
+	ProfileService_UploadFilePromptServer
+	requests []*UploadFilePromptRequest
+}
+
+func (s *uploadStream) Recv() (*UploadFilePromptRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func uploadHeader(size int64, content []byte) *UploadFilePromptRequest {
+	sum := sha256.Sum256(content)
+	return &UploadFilePromptRequest{Data: &UploadFilePromptRequest_Header{
+		Header: &UploadFilePromptHeader{Size: size, Sha256: hex.EncodeToString(sum[:])},
+	}}
+}
+
+func uploadChunk(chunk []byte) *UploadFilePromptRequest {
+	return &UploadFilePromptRequest{Data: &UploadFilePromptRequest_Chunk{Chunk: chunk}}
+}
+
+func TestReceiveUpload(t *testing.T) {
+	content := []byte("hello, world")
+	tests := []struct {
+		name     string
+		requests []*UploadFilePromptRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "complete upload",
+			requests: []*UploadFilePromptRequest{uploadHeader(12, content), uploadChunk(content[:5]), uploadChunk(content[5:])},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "missing header",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "chunk before header",
+			requests: []*UploadFilePromptRequest{uploadChunk(content)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "announced size above the maximum",
+			requests: []*UploadFilePromptRequest{uploadHeader(1<<40, content)},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "zero size",
+			requests: []*UploadFilePromptRequest{uploadHeader(0, nil)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "more bytes than announced",
+			requests: []*UploadFilePromptRequest{uploadHeader(5, content[:5]), uploadChunk(content)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "fewer bytes than announced",
+			requests: []*UploadFilePromptRequest{uploadHeader(12, content), uploadChunk(content[:5])},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "header sent twice",
+			requests: []*UploadFilePromptRequest{uploadHeader(12, content), uploadHeader(12, content)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "checksum mismatch",
+			requests: []*UploadFilePromptRequest{uploadHeader(12, []byte("other")), uploadChunk(content)},
+			wantCode: codes.DataLoss,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := receiveUpload(&uploadStream{requests: tt.requests}, 2*MB)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("receiveUpload() code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if err == nil && !bytes.Equal(got, content) {
+				t.Errorf("receiveUpload() = %q, want %q", got, content)
+			}
+		})
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -21,8 +21,8 @@ func NewServer(addr string) *http.Server {
 
 func RunServer(ctx context.Context, server *http.Server) func() error {
 	return func() error {
-		log.Printf("starting http server, listening on %s\n", server.Addr)
-		defer log.Printf("close http server listening on %s\n", server.Addr)
+		slog.Info("starting http server", "address", server.Addr)
+		defer slog.Info("close http server", "address", server.Addr)
 
 		errCh := make(chan error)
 
@@ -31,7 +31,7 @@ func RunServer(ctx context.Context, server *http.Server) func() error {
 			defer cancel()
 
 			if err := server.Shutdown(shCtx); err != nil {
-				log.Printf("can't close http server listening on %s: %s", server.Addr, err.Error())
+				slog.Error("can't close http server", "address", server.Addr, "error", err)
 			}
 
 			close(errCh)
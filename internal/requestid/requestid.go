@@ -0,0 +1,45 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the metadata key carrying the request ID between services.
+const Header = "x-request-id"
+
+// MaxLength bounds the IDs accepted from callers.
+const MaxLength = 128
+
+type ctxKey struct{}
+
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether an ID received from a caller can be logged and
+// forwarded: it must be at most MaxLength letters, digits, dots, dashes
+// and underscores.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,37 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: "0b6f7a4e-3c1d-4f0e-9a57-2f8e1b6c9d10", want: true},
+		{name: "dots and underscores", id: "edge_1.req-42", want: true},
+		{name: "max length", id: strings.Repeat("a", MaxLength), want: true},
+		{name: "empty", id: ""},
+		{name: "too long", id: strings.Repeat("a", MaxLength+1)},
+		{name: "newline", id: "req-1\nlevel=ERROR"},
+		{name: "space", id: "req 1"},
+		{name: "quote", id: `req"1`},
+		{name: "non-ascii", id: "req-é"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.id); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewIsValid(t *testing.T) {
+	if id := New(); !Valid(id) {
+		t.Errorf("New() = %q is not valid", id)
+	}
+}